@@ -0,0 +1,262 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/zsuzuki/csvfilter/csvio"
+	"github.com/zsuzuki/csvfilter/filterexpr"
+	"github.com/zsuzuki/csvfilter/pkg/csvfilter"
+)
+
+type options struct {
+	filePath       string
+	filterCol      string
+	filterValue    string
+	wheres         stringList
+	orMode         bool
+	sortCol        string
+	sortType       string
+	encoding       string
+	outputEncoding string
+
+	delim           string
+	comment         string
+	noHeader        bool
+	header          string
+	skip            int
+	lazyQuotes      bool
+	fieldsPerRecord int
+	schemaSpec      string
+
+	selectCols string
+	dropCols   string
+	adds       stringList
+
+	sortBuffer int
+	progress   bool
+}
+
+// stringList accumulates a repeatable string flag, e.g. -where a -where b.
+type stringList []string
+
+func (s *stringList) String() string { return strings.Join(*s, ",") }
+
+func (s *stringList) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+// main is a thin wrapper over pkg/csvfilter: it resolves csvio's input
+// dialect (encoding, delimiter, headers) into rows, drives them through
+// csvfilter.FilterRows for filtering/projection/sort, and writes the result
+// with csvfilter.Write.
+func main() {
+	opts := parseFlags()
+
+	rr, err := csvio.OpenRows(opts.filePath, csvio.Options{
+		Encoding:        opts.encoding,
+		Delimiter:       opts.delim,
+		Comment:         opts.comment,
+		NoHeader:        opts.noHeader,
+		Header:          opts.header,
+		Skip:            opts.skip,
+		LazyQuotes:      opts.lazyQuotes,
+		FieldsPerRecord: opts.fieldsPerRecord,
+	})
+	if err != nil {
+		exitWithError(err)
+	}
+	defer rr.Close()
+
+	headers := rr.Headers
+	if headers == nil {
+		return
+	}
+
+	extraWhere, err := buildLegacyFilter(opts)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	var progress *progressReporter
+	if opts.progress {
+		progress = newProgressReporter(os.Stderr)
+	}
+	next := rr.Read
+	if progress != nil {
+		next = func() ([]string, error) {
+			row, err := rr.Read()
+			if err == nil {
+				progress.tick()
+			}
+			return row, err
+		}
+	}
+
+	outHeaders, rows, err := csvfilter.FilterRows[[]string](headers, next, csvfilter.Options{
+		Where:        opts.wheres,
+		ExtraWhere:   extraWhere,
+		Or:           opts.orMode,
+		Schema:       opts.schemaSpec,
+		Add:          opts.adds,
+		Select:       opts.selectCols,
+		Drop:         opts.dropCols,
+		Sort:         opts.sortCol,
+		SortFallback: opts.sortType,
+		SortBuffer:   opts.sortBuffer,
+	})
+	if err != nil {
+		exitWithError(err)
+	}
+
+	encoded, err := csvio.EncodeWriter(os.Stdout, opts.outputEncoding)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	// Write[[]string] writes no header of its own (the caller owns it), so
+	// the projected header row is written with its own csv.Writer first.
+	headerWriter := csv.NewWriter(encoded)
+	if err := headerWriter.Write(outHeaders); err != nil {
+		exitWithError(err)
+	}
+	headerWriter.Flush()
+	if err := headerWriter.Error(); err != nil {
+		exitWithError(err)
+	}
+
+	var rowErr error
+	if err := csvfilter.Write[[]string](encoded, valuesOnly(rows, &rowErr)); err != nil {
+		exitWithError(err)
+	}
+	if rowErr != nil {
+		exitWithError(rowErr)
+	}
+
+	if progress != nil {
+		progress.done()
+	}
+
+	if c, ok := encoded.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			exitWithError(err)
+		}
+	}
+}
+
+// valuesOnly adapts rows (as returned by csvfilter.FilterRows) to the
+// iter.Seq[T] csvfilter.Write expects, stopping and storing the first error
+// in *err instead of yielding it as a value.
+func valuesOnly[T any](rows iter.Seq2[T, error], err *error) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v, e := range rows {
+			if e != nil {
+				*err = e
+				return
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// buildLegacyFilter lowers the legacy -filter/-value flags to a
+// filterexpr.Contains node, built directly rather than as text so an
+// arbitrary column name never has to round-trip through the expression
+// parser.
+func buildLegacyFilter(opts options) ([]filterexpr.Node, error) {
+	if opts.filterCol == "" && opts.filterValue == "" {
+		return nil, nil
+	}
+	if opts.filterCol == "" || opts.filterValue == "" {
+		return nil, errors.New("both -filter and -value must be specified")
+	}
+	return []filterexpr.Node{filterexpr.Contains(opts.filterCol, opts.filterValue)}, nil
+}
+
+func parseFlags() options {
+	var opts options
+	flag.StringVar(&opts.filePath, "file", "", "CSV file path (optional, otherwise first arg or stdin)")
+	flag.StringVar(&opts.filterCol, "filter", "", "column name for filtering")
+	flag.StringVar(&opts.filterValue, "value", "", "substring to match for filtering")
+	flag.Var(&opts.wheres, "where", "filter predicate, e.g. 'price>=100' or 'name~=^AA' (repeatable, ANDed by default)")
+	flag.BoolVar(&opts.orMode, "or", false, "combine multiple -where predicates with OR instead of AND")
+	flag.StringVar(&opts.sortCol, "sort", "", "column name for sorting, or a comma-separated multi-key spec like 'region:asc:str,price:desc:num'")
+	flag.StringVar(&opts.sortType, "type", "asc", "sort direction: asc/desc or lt/le/gt/ge, optionally :num or :str (e.g. asc:num)")
+	flag.StringVar(&opts.encoding, "encoding", "", "input character encoding: utf8, utf8-bom, gbk, shift-jis, euc-jp, utf16le, utf16be, auto (default utf8)")
+	flag.StringVar(&opts.outputEncoding, "output-encoding", "", "output character encoding, same values as -encoding (except auto)")
+	flag.StringVar(&opts.delim, "delim", "", "field delimiter: ',' (default), '\\t', ';', '|' or any single character")
+	flag.StringVar(&opts.comment, "comment", "", "lines starting with this character are dropped before parsing")
+	flag.BoolVar(&opts.noHeader, "no-header", false, "treat input as headerless; synthesizes col1..colN names")
+	flag.StringVar(&opts.header, "header", "", "override the header row with these comma-separated column names")
+	flag.IntVar(&opts.skip, "skip", 0, "number of raw lines to discard before CSV parsing begins")
+	flag.BoolVar(&opts.lazyQuotes, "lazy-quotes", false, "relax quote parsing like encoding/csv's LazyQuotes")
+	flag.IntVar(&opts.fieldsPerRecord, "fields-per-record", 0, "expected field count per row: 0 auto-detects, negative disables the check")
+	flag.StringVar(&opts.schemaSpec, "schema", "", "typed columns, e.g. 'price:num,qty:int,active:bool,ts:time=2006-01-02'")
+	flag.StringVar(&opts.selectCols, "select", "", "restrict and reorder output columns, e.g. 'name,price,region as r'")
+	flag.StringVar(&opts.dropCols, "drop", "", "drop these comma-separated columns from output")
+	flag.Var(&opts.adds, "add", "computed column, e.g. 'total=price*qty' or 'tag=upper(region)' (repeatable)")
+	flag.IntVar(&opts.sortBuffer, "sort-buffer", csvfilter.DefaultSortBuffer, "max rows buffered in memory before -sort spills to disk")
+	flag.BoolVar(&opts.progress, "progress", false, "log row-processing progress to stderr")
+	flag.Parse()
+
+	if opts.filePath == "" {
+		args := flag.Args()
+		if len(args) > 0 {
+			opts.filePath = args[0]
+		}
+	}
+
+	return opts
+}
+
+// progressReporter logs row-processing throughput to stderr at most once a
+// second, so -progress doesn't itself become the bottleneck on fast runs.
+type progressReporter struct {
+	w       io.Writer
+	rows    int64
+	start   time.Time
+	lastLog time.Time
+}
+
+func newProgressReporter(w io.Writer) *progressReporter {
+	now := time.Now()
+	return &progressReporter{w: w, start: now, lastLog: now}
+}
+
+func (p *progressReporter) tick() {
+	p.rows++
+	now := time.Now()
+	if now.Sub(p.lastLog) < time.Second {
+		return
+	}
+	p.lastLog = now
+	p.log(now)
+}
+
+func (p *progressReporter) done() {
+	p.log(time.Now())
+}
+
+func (p *progressReporter) log(now time.Time) {
+	elapsed := now.Sub(p.start).Seconds()
+	rate := float64(p.rows)
+	if elapsed > 0 {
+		rate = float64(p.rows) / elapsed
+	}
+	fmt.Fprintf(p.w, "processed %d rows (%.0f rows/sec)\n", p.rows, rate)
+}
+
+func exitWithError(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -0,0 +1,42 @@
+// Package csvio provides the CSV input/output helpers shared by the
+// csvfilter command: opening files or stdin, decoding/encoding character
+// sets, and configuring the underlying encoding/csv reader (delimiter,
+// comment lines, header handling, quoting).
+package csvio
+
+// Options configures how RowReader and RowWriter read and write CSV data.
+type Options struct {
+	// Encoding is the character encoding of the input data. Supported
+	// values: "", "utf8", "utf8-bom", "gbk", "shift-jis", "euc-jp",
+	// "utf16le", "utf16be", "auto" (sniff the BOM). An empty value means
+	// "utf8".
+	Encoding string
+	// OutputEncoding is the character encoding to write output in. Accepts
+	// the same values as Encoding except "auto".
+	OutputEncoding string
+
+	// Delimiter is the field delimiter, e.g. "," (default), "\t", ";" or
+	// "|". Must resolve to exactly one character.
+	Delimiter string
+	// Comment, if set, marks lines whose first character matches it as
+	// comments; such lines are dropped before parsing. Must resolve to
+	// exactly one character.
+	Comment string
+	// NoHeader treats the input as headerless: synthetic names col1..colN
+	// are generated from the width of the first data row.
+	NoHeader bool
+	// Header, if non-empty, overrides the header row with these
+	// comma-separated (respecting Delimiter) column names.
+	Header string
+	// Skip is the number of raw lines to discard before CSV parsing
+	// begins, e.g. to drop a non-CSV preamble.
+	Skip int
+	// LazyQuotes relaxes quote parsing the same way csv.Reader.LazyQuotes
+	// does.
+	LazyQuotes bool
+	// FieldsPerRecord is passed through to csv.Reader.FieldsPerRecord: 0
+	// (the default) auto-detects from the first record, a positive value
+	// enforces an exact field count, and a negative value disables the
+	// check.
+	FieldsPerRecord int
+}
@@ -0,0 +1,189 @@
+package csvio
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func readAllRows(t *testing.T, rr *RowReader) [][]string {
+	t.Helper()
+	var rows [][]string
+	for {
+		row, err := rr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Read: %v", err)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func TestOpenRowsDefaultHeader(t *testing.T) {
+	f := writeTemp(t, "name,age\nAlice,30\nBob,25\n")
+
+	rr, err := OpenRows(f, Options{})
+	if err != nil {
+		t.Fatalf("OpenRows: %v", err)
+	}
+	defer rr.Close()
+
+	if got, want := rr.Headers, []string{"name", "age"}; !equal(got, want) {
+		t.Fatalf("Headers = %v, want %v", got, want)
+	}
+	rows := readAllRows(t, rr)
+	if len(rows) != 2 || !equal(rows[0], []string{"Alice", "30"}) {
+		t.Fatalf("rows = %v", rows)
+	}
+}
+
+func TestOpenRowsNoHeaderSynthesizesNames(t *testing.T) {
+	f := writeTemp(t, "Alice,30\nBob,25\n")
+
+	rr, err := OpenRows(f, Options{NoHeader: true})
+	if err != nil {
+		t.Fatalf("OpenRows: %v", err)
+	}
+	defer rr.Close()
+
+	if got, want := rr.Headers, []string{"col1", "col2"}; !equal(got, want) {
+		t.Fatalf("Headers = %v, want %v", got, want)
+	}
+	rows := readAllRows(t, rr)
+	if len(rows) != 2 || !equal(rows[0], []string{"Alice", "30"}) {
+		t.Fatalf("rows = %v, want first row [Alice 30] still present after the peek used to size headers", rows)
+	}
+}
+
+func TestOpenRowsHeaderOverride(t *testing.T) {
+	f := writeTemp(t, "a,b\n1,2\n")
+
+	rr, err := OpenRows(f, Options{Header: "x,y"})
+	if err != nil {
+		t.Fatalf("OpenRows: %v", err)
+	}
+	defer rr.Close()
+
+	if got, want := rr.Headers, []string{"x", "y"}; !equal(got, want) {
+		t.Fatalf("Headers = %v, want %v", got, want)
+	}
+	rows := readAllRows(t, rr)
+	if len(rows) != 1 || !equal(rows[0], []string{"1", "2"}) {
+		t.Fatalf("rows = %v, want original first row preserved as data", rows)
+	}
+}
+
+func TestOpenRowsSkipAndComment(t *testing.T) {
+	f := writeTemp(t, "preamble line\nname,age\n#comment\nAlice,30\n")
+
+	rr, err := OpenRows(f, Options{Skip: 1, Comment: "#"})
+	if err != nil {
+		t.Fatalf("OpenRows: %v", err)
+	}
+	defer rr.Close()
+
+	rows := readAllRows(t, rr)
+	if len(rows) != 1 || !equal(rows[0], []string{"Alice", "30"}) {
+		t.Fatalf("rows = %v", rows)
+	}
+}
+
+func TestOpenRowsCustomDelimiter(t *testing.T) {
+	f := writeTemp(t, "name;age\nAlice;30\n")
+
+	rr, err := OpenRows(f, Options{Delimiter: ";"})
+	if err != nil {
+		t.Fatalf("OpenRows: %v", err)
+	}
+	defer rr.Close()
+
+	if got, want := rr.Headers, []string{"name", "age"}; !equal(got, want) {
+		t.Fatalf("Headers = %v, want %v", got, want)
+	}
+}
+
+func TestRowWriterRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	rw, err := OpenRowWriter(&buf, Options{})
+	if err != nil {
+		t.Fatalf("OpenRowWriter: %v", err)
+	}
+	if err := rw.Write([]string{"name", "age"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := rw.Write([]string{"Alice", "30"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := rw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	want := "name,age\nAlice,30\n"
+	if buf.String() != want {
+		t.Fatalf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestParseDelimiter(t *testing.T) {
+	cases := []struct {
+		in   string
+		want rune
+	}{
+		{"", ','},
+		{"\\t", '\t'},
+		{";", ';'},
+		{"|", '|'},
+	}
+	for _, c := range cases {
+		got, err := parseDelimiter(c.in)
+		if err != nil {
+			t.Fatalf("parseDelimiter(%q): %v", c.in, err)
+		}
+		if got != c.want {
+			t.Fatalf("parseDelimiter(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+
+	if _, err := parseDelimiter("ab"); err == nil {
+		t.Fatal("expected error for a multi-character delimiter")
+	}
+}
+
+func TestDecodeReaderUnsupportedEncoding(t *testing.T) {
+	if _, err := DecodeReader(strings.NewReader(""), "bogus"); err == nil {
+		t.Fatal("expected error for an unsupported encoding")
+	}
+}
+
+func TestEncodeWriterUnsupportedEncoding(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := EncodeWriter(&buf, "bogus"); err == nil {
+		t.Fatal("expected error for an unsupported output encoding")
+	}
+}
+
+func writeTemp(t *testing.T, content string) string {
+	t.Helper()
+	f := t.TempDir() + "/input.csv"
+	if err := os.WriteFile(f, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return f
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package csvio
+
+import (
+	"fmt"
+	"io"
+
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// DecodeReader wraps r so reads come out as UTF-8, converting from enc. An
+// empty enc (or "utf8") returns r unchanged. "auto" sniffs a BOM on the
+// first bytes read and picks utf8, utf16le or utf16be accordingly, falling
+// back to plain utf8 when no BOM is present.
+func DecodeReader(r io.Reader, enc string) (io.Reader, error) {
+	switch enc {
+	case "", "utf8":
+		return r, nil
+	case "auto", "utf8-bom":
+		return transform.NewReader(r, unicode.BOMOverride(unicode.UTF8.NewDecoder())), nil
+	case "utf16le":
+		return transform.NewReader(r, unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder()), nil
+	case "utf16be":
+		return transform.NewReader(r, unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewDecoder()), nil
+	case "gbk":
+		return transform.NewReader(r, simplifiedchinese.GBK.NewDecoder()), nil
+	case "shift-jis":
+		return transform.NewReader(r, japanese.ShiftJIS.NewDecoder()), nil
+	case "euc-jp":
+		return transform.NewReader(r, japanese.EUCJP.NewDecoder()), nil
+	default:
+		return nil, fmt.Errorf("unsupported encoding: %s", enc)
+	}
+}
+
+// EncodeWriter wraps w so writes given as UTF-8 are converted to enc before
+// reaching w. An empty enc (or "utf8") returns w unchanged. "utf8-bom" and
+// the utf16 variants emit a leading BOM.
+func EncodeWriter(w io.Writer, enc string) (io.Writer, error) {
+	switch enc {
+	case "", "utf8":
+		return w, nil
+	case "utf8-bom":
+		return transform.NewWriter(w, unicode.UTF8BOM.NewEncoder()), nil
+	case "utf16le":
+		return transform.NewWriter(w, unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder()), nil
+	case "utf16be":
+		return transform.NewWriter(w, unicode.UTF16(unicode.BigEndian, unicode.UseBOM).NewEncoder()), nil
+	case "gbk":
+		return transform.NewWriter(w, simplifiedchinese.GBK.NewEncoder()), nil
+	case "shift-jis":
+		return transform.NewWriter(w, japanese.ShiftJIS.NewEncoder()), nil
+	case "euc-jp":
+		return transform.NewWriter(w, japanese.EUCJP.NewEncoder()), nil
+	default:
+		return nil, fmt.Errorf("unsupported output encoding: %s", enc)
+	}
+}
@@ -0,0 +1,105 @@
+package csvio
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// openReader opens filePath (or stdin when empty), decodes it, discards
+// Options.Skip raw lines, and returns a csv.Reader configured per Options.
+// The header row, if any, is still unconsumed.
+func openReader(filePath string, opts Options) (*csv.Reader, io.Closer, error) {
+	var (
+		r      io.Reader
+		closer io.Closer = nopCloser{}
+	)
+	if filePath != "" {
+		f, err := os.Open(filePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		r, closer = f, f
+	} else {
+		r = os.Stdin
+	}
+
+	decoded, err := DecodeReader(r, opts.Encoding)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	br := bufio.NewReader(decoded)
+	for i := 0; i < opts.Skip; i++ {
+		if _, err := br.ReadString('\n'); err != nil && err != io.EOF {
+			return nil, nil, err
+		}
+	}
+
+	csvr := csv.NewReader(br)
+	if err := configureReader(csvr, opts); err != nil {
+		return nil, nil, err
+	}
+
+	return csvr, closer, nil
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
+
+func configureReader(csvr *csv.Reader, opts Options) error {
+	delim, err := parseDelimiter(opts.Delimiter)
+	if err != nil {
+		return err
+	}
+	csvr.Comma = delim
+
+	if opts.Comment != "" {
+		comment, err := parseSingleRune(opts.Comment)
+		if err != nil {
+			return fmt.Errorf("invalid -comment: %w", err)
+		}
+		csvr.Comment = comment
+	}
+
+	csvr.LazyQuotes = opts.LazyQuotes
+	csvr.FieldsPerRecord = opts.FieldsPerRecord
+
+	return nil
+}
+
+func parseHeaderLine(header string, delim rune) ([]string, error) {
+	hr := csv.NewReader(strings.NewReader(header))
+	hr.Comma = delim
+	fields, err := hr.Read()
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// parseDelimiter resolves a delimiter flag value to a single rune, accepting
+// the common shell-escaped form "\t" for tab in addition to a literal
+// character.
+func parseDelimiter(s string) (rune, error) {
+	switch s {
+	case "":
+		return ',', nil
+	case "\\t":
+		return '\t', nil
+	default:
+		return parseSingleRune(s)
+	}
+}
+
+func parseSingleRune(s string) (rune, error) {
+	runes := []rune(s)
+	if len(runes) != 1 {
+		return 0, fmt.Errorf("expected a single character, got %q", s)
+	}
+	return runes[0], nil
+}
@@ -0,0 +1,104 @@
+package csvio
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// RowReader reads CSV rows one at a time, so a caller can filter, project
+// and sort a file without ever holding it all in memory.
+type RowReader struct {
+	csvr    *csv.Reader
+	closer  io.Closer
+	Headers []string
+
+	peeked     []string
+	havePeeked bool
+}
+
+// OpenRows opens filePath (or stdin when empty) for row-at-a-time reading,
+// applying Options the same way ReadCSV does. Headers is populated
+// immediately; the first data row may be consumed in the process (a real
+// header row, or the row peeked to size a NoHeader synthetic header) and is
+// buffered for the first Read call.
+func OpenRows(filePath string, opts Options) (*RowReader, error) {
+	csvr, closer, err := openReader(filePath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	rr := &RowReader{csvr: csvr, closer: closer}
+	if err := rr.resolveHeaders(opts); err != nil {
+		closer.Close()
+		return nil, err
+	}
+	return rr, nil
+}
+
+func (rr *RowReader) resolveHeaders(opts Options) error {
+	switch {
+	case opts.NoHeader && opts.Header != "":
+		header, err := parseHeaderLine(opts.Header, rr.csvr.Comma)
+		if err != nil {
+			return fmt.Errorf("invalid -header: %w", err)
+		}
+		rr.Headers = header
+
+	case opts.NoHeader:
+		row, err := rr.csvr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		rr.Headers = syntheticHeaders(len(row))
+		rr.peeked, rr.havePeeked = row, true
+
+	case opts.Header != "":
+		if _, err := rr.csvr.Read(); err != nil && err != io.EOF {
+			return err
+		}
+		header, err := parseHeaderLine(opts.Header, rr.csvr.Comma)
+		if err != nil {
+			return fmt.Errorf("invalid -header: %w", err)
+		}
+		rr.Headers = header
+
+	default:
+		row, err := rr.csvr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		rr.Headers = row
+	}
+
+	return nil
+}
+
+func syntheticHeaders(n int) []string {
+	headers := make([]string, n)
+	for i := range headers {
+		headers[i] = fmt.Sprintf("col%d", i+1)
+	}
+	return headers
+}
+
+// Read returns the next data row, or io.EOF once the input is exhausted.
+func (rr *RowReader) Read() ([]string, error) {
+	if rr.havePeeked {
+		row := rr.peeked
+		rr.peeked, rr.havePeeked = nil, false
+		return row, nil
+	}
+	return rr.csvr.Read()
+}
+
+// Close releases the underlying file, if any.
+func (rr *RowReader) Close() error {
+	return rr.closer.Close()
+}
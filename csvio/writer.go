@@ -0,0 +1,40 @@
+package csvio
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// RowWriter writes CSV rows one at a time, for streaming output alongside
+// RowReader.
+type RowWriter struct {
+	csvw    *csv.Writer
+	encoded io.Writer
+}
+
+// OpenRowWriter wraps w for row-at-a-time writing, encoding to
+// Options.OutputEncoding.
+func OpenRowWriter(w io.Writer, opts Options) (*RowWriter, error) {
+	encoded, err := EncodeWriter(w, opts.OutputEncoding)
+	if err != nil {
+		return nil, err
+	}
+	return &RowWriter{csvw: csv.NewWriter(encoded), encoded: encoded}, nil
+}
+
+// Write writes one row.
+func (rw *RowWriter) Write(row []string) error {
+	return rw.csvw.Write(row)
+}
+
+// Close flushes buffered output and closes the underlying encoder, if any.
+func (rw *RowWriter) Close() error {
+	rw.csvw.Flush()
+	if err := rw.csvw.Error(); err != nil {
+		return err
+	}
+	if c, ok := rw.encoded.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+// Package extsort implements an external merge sort for row streams too
+// large to hold in memory: sorted runs of up to bufferRows rows are spilled
+// to temp files, then merged back together with a k-way heap merge.
+package extsort
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/zsuzuki/csvfilter/rowsort"
+)
+
+// Run reads rows from next (which returns io.EOF when exhausted), sorts
+// them against keys, and calls emit for each row in sorted order.
+//
+// Up to bufferRows rows are held in memory at a time. If the input fits
+// within that, it's sorted and emitted directly with no disk I/O. Once
+// exceeded, each full buffer is sorted and spilled to a temp file as a
+// run; all runs (spilled plus any final partial buffer) are then merged
+// with a k-way heap merge, so peak memory stays bounded by bufferRows
+// regardless of input size.
+//
+// Each row is tagged with its original read order as it's consumed from
+// next, and that order is used as the final tiebreaker during the merge.
+// Without it, rows with equal sort keys would come back in an order that
+// depends on how the input happened to be split across runs, instead of
+// matching the stable, input-order tiebreak an in-memory sort.Stable gives.
+func Run(next func() ([]string, error), keys []rowsort.Key, bufferRows int, emit func(row []string) error) error {
+	var runs []rowSource
+	defer func() {
+		for _, r := range runs {
+			r.Close()
+		}
+	}()
+
+	var seq int64
+	buf := make([]seqRow, 0, bufferRows)
+	for {
+		row, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		buf = append(buf, seqRow{seq: seq, row: row})
+		seq++
+		if len(buf) >= bufferRows {
+			run, err := spill(buf, keys)
+			if err != nil {
+				return err
+			}
+			runs = append(runs, run)
+			buf = make([]seqRow, 0, bufferRows)
+		}
+	}
+
+	if len(runs) == 0 {
+		sortSeqRows(buf, keys)
+		for _, sr := range buf {
+			if err := emit(sr.row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(buf) > 0 {
+		sortSeqRows(buf, keys)
+		runs = append(runs, newMemRun(buf))
+	}
+
+	return merge(runs, keys, emit)
+}
+
+// seqRow is a row tagged with its original global read order, so ties on
+// the sort keys can still be broken by input order after rows have been
+// split across spilled runs.
+type seqRow struct {
+	seq int64
+	row []string
+}
+
+// sortSeqRows stable-sorts buf against keys via rowsort.SortFunc, keeping
+// each row paired with its seq across swaps.
+func sortSeqRows(buf []seqRow, keys []rowsort.Key) {
+	rowsort.SortFunc(len(buf), keys, func(i int) []string { return buf[i].row }, func(i, j int) {
+		buf[i], buf[j] = buf[j], buf[i]
+	})
+}
+
+// rowSource yields rows in already-sorted order, each tagged with its
+// original seq so the merge can tiebreak across runs.
+type rowSource interface {
+	Next() (seqRow, error)
+	Close() error
+}
+
+type memRun struct {
+	rows []seqRow
+	pos  int
+}
+
+func newMemRun(rows []seqRow) *memRun { return &memRun{rows: rows} }
+
+func (r *memRun) Next() (seqRow, error) {
+	if r.pos >= len(r.rows) {
+		return seqRow{}, io.EOF
+	}
+	row := r.rows[r.pos]
+	r.pos++
+	return row, nil
+}
+
+func (r *memRun) Close() error { return nil }
+
+type fileRun struct {
+	f   *os.File
+	csv *csv.Reader
+}
+
+func (r *fileRun) Next() (seqRow, error) {
+	rec, err := r.csv.Read()
+	if err != nil {
+		return seqRow{}, err
+	}
+	seq, err := strconv.ParseInt(rec[0], 10, 64)
+	if err != nil {
+		return seqRow{}, err
+	}
+	return seqRow{seq: seq, row: rec[1:]}, nil
+}
+
+func (r *fileRun) Close() error {
+	err := r.f.Close()
+	os.Remove(r.f.Name())
+	return err
+}
+
+// spill sorts rows and writes them to a temp file as a new run, with each
+// row's seq persisted as a leading field so it survives the round trip
+// through disk.
+func spill(rows []seqRow, keys []rowsort.Key) (rowSource, error) {
+	sortSeqRows(rows, keys)
+
+	f, err := os.CreateTemp("", "csvfilter-sort-*.csv")
+	if err != nil {
+		return nil, err
+	}
+
+	w := csv.NewWriter(f)
+	for _, sr := range rows {
+		rec := append([]string{strconv.FormatInt(sr.seq, 10)}, sr.row...)
+		if err := w.Write(rec); err != nil {
+			f.Close()
+			os.Remove(f.Name())
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	return &fileRun{f: f, csv: csv.NewReader(f)}, nil
+}
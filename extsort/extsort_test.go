@@ -0,0 +1,115 @@
+package extsort
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/zsuzuki/csvfilter/rowsort"
+)
+
+// rowsFixture builds n rows of id,price, with two rows (at idxA and idxB,
+// idxA < idxB) sharing the same price so their relative order on a
+// price-only sort is a pure tiebreak.
+func rowsFixture(n, idxA, idxB, tiedPrice int) [][]string {
+	rows := make([][]string, n)
+	for i := range rows {
+		price := tiedPrice
+		if i != idxA && i != idxB {
+			price = tiedPrice + 1 + i%50
+		}
+		rows[i] = []string{fmt.Sprintf("%d", i), fmt.Sprintf("%d", price)}
+	}
+	return rows
+}
+
+func runSort(t *testing.T, rows [][]string, bufferRows int) [][]string {
+	t.Helper()
+	keys := []rowsort.Key{{Idx: 1, Dir: -1, Mode: rowsort.ModeNum}}
+
+	i := 0
+	next := func() ([]string, error) {
+		if i >= len(rows) {
+			return nil, io.EOF
+		}
+		row := rows[i]
+		i++
+		return row, nil
+	}
+
+	var out [][]string
+	err := Run(next, keys, bufferRows, func(row []string) error {
+		out = append(out, row)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	return out
+}
+
+// TestRunTieOrderStableAcrossSpillBoundary reproduces the bug where rows
+// tied on the sort key came back in a different relative order depending
+// on whether -sort-buffer forced the input to spill across multiple runs.
+func TestRunTieOrderStableAcrossSpillBoundary(t *testing.T) {
+	rows := rowsFixture(200, 40, 180, 5)
+
+	noSpill := runSort(t, rows, 500)
+	spilled := runSort(t, rows, 10)
+
+	idsNoSpill := ids(noSpill)
+	idsSpilled := ids(spilled)
+
+	if !slices.Equal(idsNoSpill, idsSpilled) {
+		t.Fatalf("tie order differs with spilling: no-spill=%v spilled=%v", idsNoSpill, idsSpilled)
+	}
+
+	tiedNoSpill := tiedIDs(noSpill, "5")
+	if !slices.Equal(tiedNoSpill, []string{"40", "180"}) {
+		t.Fatalf("expected tied rows in original input order [40 180], got %v", tiedNoSpill)
+	}
+}
+
+func ids(rows [][]string) []string {
+	out := make([]string, len(rows))
+	for i, r := range rows {
+		out[i] = r[0]
+	}
+	return out
+}
+
+func tiedIDs(rows [][]string, price string) []string {
+	var out []string
+	for _, r := range rows {
+		if r[1] == price {
+			out = append(out, r[0])
+		}
+	}
+	return out
+}
+
+func TestRunNoSortKeysPassesThrough(t *testing.T) {
+	rows := [][]string{{"1", "a"}, {"2", "b"}}
+	i := 0
+	next := func() ([]string, error) {
+		if i >= len(rows) {
+			return nil, io.EOF
+		}
+		row := rows[i]
+		i++
+		return row, nil
+	}
+
+	var out [][]string
+	err := Run(next, nil, 500, func(row []string) error {
+		out = append(out, row)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !slices.Equal(ids(out), []string{"1", "2"}) {
+		t.Fatalf("got %v, want input order preserved", out)
+	}
+}
@@ -0,0 +1,84 @@
+package extsort
+
+import (
+	"container/heap"
+	"io"
+
+	"github.com/zsuzuki/csvfilter/rowsort"
+)
+
+// merge k-way merges already-sorted runs via a min-heap, emitting rows in
+// overall sorted order.
+func merge(runs []rowSource, keys []rowsort.Key, emit func(row []string) error) error {
+	h := &runHeap{keys: keys}
+	for i, r := range runs {
+		sr, err := r.Next()
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		h.items = append(h.items, runItem{seqRow: sr, runIdx: i})
+	}
+	heap.Init(h)
+
+	for h.Len() > 0 {
+		item := heap.Pop(h).(runItem)
+		if err := emit(item.row); err != nil {
+			return err
+		}
+
+		next, err := runs[item.runIdx].Next()
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		heap.Push(h, runItem{seqRow: next, runIdx: item.runIdx})
+	}
+
+	return nil
+}
+
+type runItem struct {
+	seqRow
+	runIdx int
+}
+
+// runHeap is a container/heap.Interface min-heap over the current front row
+// of each run, ordered by the shared multi-key comparator. Runs are already
+// internally ordered by seq on ties (sortSeqRows is stable), but the heap
+// merge loses that ordering across runs unless seq is also consulted here:
+// two rows with equal keys, one the head of run A and the other the head of
+// run B, have no inherent order from rowsort.Compare alone, so the result
+// would depend on which run happened to spill first. Falling back to seq
+// makes the merge's output identical to an in-memory sort.Stable regardless
+// of how the input was split into runs.
+type runHeap struct {
+	items []runItem
+	keys  []rowsort.Key
+}
+
+func (h *runHeap) Len() int { return len(h.items) }
+
+func (h *runHeap) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if c := rowsort.Compare(a.row, b.row, h.keys); c != 0 {
+		return c < 0
+	}
+	return a.seq < b.seq
+}
+
+func (h *runHeap) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *runHeap) Push(x any) { h.items = append(h.items, x.(runItem)) }
+
+func (h *runHeap) Pop() any {
+	old := h.items
+	n := len(old)
+	item := old[n-1]
+	h.items = old[:n-1]
+	return item
+}
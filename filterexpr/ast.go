@@ -0,0 +1,191 @@
+package filterexpr
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type containsKind int
+
+const (
+	kindContains containsKind = iota
+	kindStartsWith
+	kindEndsWith
+)
+
+type andNode struct {
+	left, right Node
+}
+
+func (n *andNode) Compile(colIndex map[string]int, isNumeric func(col string) bool) (Compiled, error) {
+	left, err := n.left.Compile(colIndex, isNumeric)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.Compile(colIndex, isNumeric)
+	if err != nil {
+		return nil, err
+	}
+	return func(row []string) (bool, error) {
+		ok, err := left(row)
+		if err != nil || !ok {
+			return false, err
+		}
+		return right(row)
+	}, nil
+}
+
+type orNode struct {
+	left, right Node
+}
+
+func (n *orNode) Compile(colIndex map[string]int, isNumeric func(col string) bool) (Compiled, error) {
+	left, err := n.left.Compile(colIndex, isNumeric)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.Compile(colIndex, isNumeric)
+	if err != nil {
+		return nil, err
+	}
+	return func(row []string) (bool, error) {
+		ok, err := left(row)
+		if err != nil || ok {
+			return ok, err
+		}
+		return right(row)
+	}, nil
+}
+
+type compareNode struct {
+	col   string
+	op    tokenKind
+	value string
+}
+
+func (n *compareNode) Compile(colIndex map[string]int, isNumeric func(col string) bool) (Compiled, error) {
+	idx, ok := colIndex[n.col]
+	if !ok {
+		return nil, fmt.Errorf("where: column not found: %s", n.col)
+	}
+	valNum, valIsNum := parseFloat(n.value)
+	schemaNumeric := valIsNum && isNumeric != nil && isNumeric(n.col)
+
+	return func(row []string) (bool, error) {
+		v := valueAt(row, idx)
+		if schemaNumeric {
+			fv, ok := parseFloat(v)
+			if !ok {
+				return false, fmt.Errorf("where: column %s: schema declares numeric but value is not: %s", n.col, v)
+			}
+			return compareOp(n.op, fv < valNum, fv == valNum, fv > valNum)
+		}
+		if valIsNum {
+			if fv, ok := parseFloat(v); ok {
+				return compareOp(n.op, fv < valNum, fv == valNum, fv > valNum)
+			}
+		}
+		return compareOp(n.op, v < n.value, v == n.value, v > n.value)
+	}, nil
+}
+
+func compareOp(op tokenKind, lt, eq, gt bool) (bool, error) {
+	switch op {
+	case tokEQ:
+		return eq, nil
+	case tokNEQ:
+		return !eq, nil
+	case tokLT:
+		return lt, nil
+	case tokLE:
+		return lt || eq, nil
+	case tokGT:
+		return gt, nil
+	case tokGE:
+		return gt || eq, nil
+	default:
+		return false, fmt.Errorf("unsupported comparison operator")
+	}
+}
+
+type regexNode struct {
+	col     string
+	pattern *regexp.Regexp
+	negate  bool
+}
+
+func (n *regexNode) Compile(colIndex map[string]int, isNumeric func(col string) bool) (Compiled, error) {
+	idx, ok := colIndex[n.col]
+	if !ok {
+		return nil, fmt.Errorf("where: column not found: %s", n.col)
+	}
+	return func(row []string) (bool, error) {
+		m := n.pattern.MatchString(valueAt(row, idx))
+		if n.negate {
+			return !m, nil
+		}
+		return m, nil
+	}, nil
+}
+
+type containsNode struct {
+	col   string
+	value string
+	kind  containsKind
+}
+
+func (n *containsNode) Compile(colIndex map[string]int, isNumeric func(col string) bool) (Compiled, error) {
+	idx, ok := colIndex[n.col]
+	if !ok {
+		return nil, fmt.Errorf("where: column not found: %s", n.col)
+	}
+	return func(row []string) (bool, error) {
+		v := valueAt(row, idx)
+		switch n.kind {
+		case kindStartsWith:
+			return strings.HasPrefix(v, n.value), nil
+		case kindEndsWith:
+			return strings.HasSuffix(v, n.value), nil
+		default:
+			return strings.Contains(v, n.value), nil
+		}
+	}, nil
+}
+
+type inNode struct {
+	col    string
+	values []string
+}
+
+func (n *inNode) Compile(colIndex map[string]int, isNumeric func(col string) bool) (Compiled, error) {
+	idx, ok := colIndex[n.col]
+	if !ok {
+		return nil, fmt.Errorf("where: column not found: %s", n.col)
+	}
+	return func(row []string) (bool, error) {
+		v := valueAt(row, idx)
+		for _, want := range n.values {
+			if v == want {
+				return true, nil
+			}
+		}
+		return false, nil
+	}, nil
+}
+
+func valueAt(row []string, idx int) string {
+	if idx < len(row) {
+		return row[idx]
+	}
+	return ""
+}
+
+func parseFloat(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
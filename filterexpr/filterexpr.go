@@ -0,0 +1,65 @@
+// Package filterexpr implements the expression language backing csvfilter's
+// -where flag: comparisons, regexp matching, contains/startswith/endswith,
+// in(...) lists, and &&/|| combinators with parentheses.
+package filterexpr
+
+import "fmt"
+
+// Compiled evaluates a predicate against a single CSV row.
+type Compiled func(row []string) (bool, error)
+
+// Node is a parsed predicate expression. Compile binds it against a column
+// header index, resolving column name lookups once so evaluation is O(1)
+// per row. isNumeric, if non-nil, is consulted by numeric comparisons: when
+// it reports a column numeric (e.g. from a -schema declaration), the
+// comparison assumes every row's value parses as a number and errors
+// cleanly on one that doesn't, instead of silently falling back to lexical
+// comparison for that row.
+type Node interface {
+	Compile(colIndex map[string]int, isNumeric func(col string) bool) (Compiled, error)
+}
+
+// Parse parses a single -where expression, e.g. "price>=100" or
+// "(a=1 || b=2) && c!=closed".
+func Parse(expr string) (Node, error) {
+	toks, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.toks) {
+		return nil, fmt.Errorf("unexpected input after expression: %q", p.toks[p.pos].lit)
+	}
+	return node, nil
+}
+
+// Contains builds the "col contains value" predicate used to lower the
+// legacy -filter/-value flags onto the same evaluator as -where.
+func Contains(col, value string) Node {
+	return &containsNode{col: col, value: value, kind: kindContains}
+}
+
+// And combines nodes with AND, short-circuiting on the first false.
+func And(nodes ...Node) Node {
+	return fold(nodes, func(a, b Node) Node { return &andNode{a, b} })
+}
+
+// Or combines nodes with OR, short-circuiting on the first true.
+func Or(nodes ...Node) Node {
+	return fold(nodes, func(a, b Node) Node { return &orNode{a, b} })
+}
+
+func fold(nodes []Node, combine func(a, b Node) Node) Node {
+	if len(nodes) == 0 {
+		return nil
+	}
+	result := nodes[0]
+	for _, n := range nodes[1:] {
+		result = combine(result, n)
+	}
+	return result
+}
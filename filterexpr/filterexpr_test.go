@@ -0,0 +1,190 @@
+package filterexpr
+
+import "testing"
+
+func eval(t *testing.T, expr string, colIndex map[string]int, row []string) bool {
+	t.Helper()
+	node, err := Parse(expr)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", expr, err)
+	}
+	compiled, err := node.Compile(colIndex, nil)
+	if err != nil {
+		t.Fatalf("Compile(%q): %v", expr, err)
+	}
+	ok, err := compiled(row)
+	if err != nil {
+		t.Fatalf("eval(%q): %v", expr, err)
+	}
+	return ok
+}
+
+func TestParseComparisonOperators(t *testing.T) {
+	colIndex := map[string]int{"price": 0, "name": 1, "status": 2}
+	row := []string{"150", "AAPL", "closed"}
+
+	cases := []struct {
+		expr string
+		want bool
+	}{
+		{"price>=100", true},
+		{"price>=200", false},
+		{"price<200", true},
+		{"price<=150", true},
+		{"price=150", true},
+		{"price!=150", false},
+		{"name~=^AA", true},
+		{"name!~^BB", true},
+		{"status=closed", true},
+		{"status!=closed", false},
+	}
+	for _, c := range cases {
+		if got := eval(t, c.expr, colIndex, row); got != c.want {
+			t.Errorf("eval(%q) = %v, want %v", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestParseContainsStartsWithEndsWith(t *testing.T) {
+	colIndex := map[string]int{"name": 0}
+	row := []string{"AAPL"}
+
+	cases := []struct {
+		expr string
+		want bool
+	}{
+		{"name contains AP", true},
+		{"name contains ZZ", false},
+		{"name startswith AA", true},
+		{"name endswith PL", true},
+	}
+	for _, c := range cases {
+		if got := eval(t, c.expr, colIndex, row); got != c.want {
+			t.Errorf("eval(%q) = %v, want %v", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestParseInList(t *testing.T) {
+	colIndex := map[string]int{"region": 0}
+
+	if !eval(t, "region in(US,JP,CA)", colIndex, []string{"JP"}) {
+		t.Error("expected JP to match in(US,JP,CA)")
+	}
+	if eval(t, "region in(US,JP,CA)", colIndex, []string{"DE"}) {
+		t.Error("expected DE not to match in(US,JP,CA)")
+	}
+}
+
+func TestParseAndOrWithParens(t *testing.T) {
+	colIndex := map[string]int{"price": 0, "region": 1}
+
+	if !eval(t, "(price>=100 || region=JP) && price<200", colIndex, []string{"50", "JP"}) {
+		t.Error("expected (price>=100 || region=JP) && price<200 to match price=50,region=JP")
+	}
+	if eval(t, "(price>=100 || region=JP) && price<200", colIndex, []string{"50", "US"}) {
+		t.Error("expected (price>=100 || region=JP) && price<200 not to match price=50,region=US")
+	}
+}
+
+func TestCompileUnknownColumnErrors(t *testing.T) {
+	node, err := Parse("missing=1")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if _, err := node.Compile(map[string]int{}, nil); err == nil {
+		t.Fatal("expected an error compiling against a column index missing the referenced column")
+	}
+}
+
+func TestContainsBuildsLegacyFilterSugar(t *testing.T) {
+	node := Contains("name", "AP")
+	compiled, err := node.Compile(map[string]int{"name": 0}, nil)
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	ok, err := compiled([]string{"AAPL"})
+	if err != nil {
+		t.Fatalf("eval: %v", err)
+	}
+	if !ok {
+		t.Error("expected Contains(\"name\", \"AP\") to match AAPL")
+	}
+}
+
+func TestAndOrShortCircuit(t *testing.T) {
+	colIndex := map[string]int{"a": 0}
+	a, err := Parse("a=1")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	b, err := Parse("a=2")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	and, err := And(a, b).Compile(colIndex, nil)
+	if err != nil {
+		t.Fatalf("Compile And: %v", err)
+	}
+	if ok, _ := and([]string{"1"}); ok {
+		t.Error("expected And(a=1, a=2) not to match a=1")
+	}
+
+	or, err := Or(a, b).Compile(colIndex, nil)
+	if err != nil {
+		t.Fatalf("Compile Or: %v", err)
+	}
+	if ok, _ := or([]string{"2"}); !ok {
+		t.Error("expected Or(a=1, a=2) to match a=2")
+	}
+}
+
+func evalValue(t *testing.T, expr string, colIndex map[string]int, row []string) string {
+	t.Helper()
+	node, err := ParseValue(expr)
+	if err != nil {
+		t.Fatalf("ParseValue(%q): %v", expr, err)
+	}
+	compiled, err := node.CompileValue(colIndex)
+	if err != nil {
+		t.Fatalf("CompileValue(%q): %v", expr, err)
+	}
+	v, err := compiled(row)
+	if err != nil {
+		t.Fatalf("eval value(%q): %v", expr, err)
+	}
+	return v
+}
+
+func TestParseValueArithmeticAndFunctions(t *testing.T) {
+	colIndex := map[string]int{"price": 0, "qty": 1, "region": 2}
+	row := []string{"10", "3", "jp"}
+
+	cases := []struct {
+		expr string
+		want string
+	}{
+		{"price*qty", "30"},
+		{"price+qty", "13"},
+		{"upper(region)", "JP"},
+		{"round(price/3, 2)", "3.33"},
+	}
+	for _, c := range cases {
+		if got := evalValue(t, c.expr, colIndex, row); got != c.want {
+			t.Errorf("evalValue(%q) = %q, want %q", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestParseValueUnaryMinus(t *testing.T) {
+	colIndex := map[string]int{"price": 0}
+	row := []string{"10"}
+
+	if got, want := evalValue(t, "-5", colIndex, row), "-5"; got != want {
+		t.Errorf("evalValue(-5) = %q, want %q", got, want)
+	}
+	if got, want := evalValue(t, "-price", colIndex, row), "-10"; got != want {
+		t.Errorf("evalValue(-price) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,143 @@
+package filterexpr
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokWord tokenKind = iota
+	tokString
+	tokEQ
+	tokNEQ
+	tokLT
+	tokLE
+	tokGT
+	tokGE
+	tokRegex
+	tokNotRegex
+	tokAnd
+	tokOr
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	lit  string
+}
+
+const wordBoundary = "()=!<>~&|, \t\n\r'\""
+
+func lex(expr string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(expr) {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			toks = append(toks, token{tokLParen, "("})
+			i++
+		case c == ')':
+			toks = append(toks, token{tokRParen, ")"})
+			i++
+		case c == ',':
+			toks = append(toks, token{tokComma, ","})
+			i++
+		case c == '\'' || c == '"':
+			s, n, err := lexQuoted(expr[i:], c)
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, token{tokString, s})
+			i += n
+		case c == '&':
+			if i+1 < len(expr) && expr[i+1] == '&' {
+				toks = append(toks, token{tokAnd, "&&"})
+				i += 2
+				continue
+			}
+			return nil, fmt.Errorf("unexpected '&' at position %d", i)
+		case c == '|':
+			if i+1 < len(expr) && expr[i+1] == '|' {
+				toks = append(toks, token{tokOr, "||"})
+				i += 2
+				continue
+			}
+			return nil, fmt.Errorf("unexpected '|' at position %d", i)
+		case c == '!':
+			if i+1 < len(expr) && expr[i+1] == '=' {
+				toks = append(toks, token{tokNEQ, "!="})
+				i += 2
+				continue
+			}
+			if i+1 < len(expr) && expr[i+1] == '~' {
+				toks = append(toks, token{tokNotRegex, "!~"})
+				i += 2
+				continue
+			}
+			return nil, fmt.Errorf("unexpected '!' at position %d", i)
+		case c == '~':
+			if i+1 < len(expr) && expr[i+1] == '=' {
+				toks = append(toks, token{tokRegex, "~="})
+				i += 2
+				continue
+			}
+			return nil, fmt.Errorf("unexpected '~' at position %d", i)
+		case c == '=':
+			toks = append(toks, token{tokEQ, "="})
+			i++
+		case c == '<':
+			if i+1 < len(expr) && expr[i+1] == '=' {
+				toks = append(toks, token{tokLE, "<="})
+				i += 2
+				continue
+			}
+			toks = append(toks, token{tokLT, "<"})
+			i++
+		case c == '>':
+			if i+1 < len(expr) && expr[i+1] == '=' {
+				toks = append(toks, token{tokGE, ">="})
+				i += 2
+				continue
+			}
+			toks = append(toks, token{tokGT, ">"})
+			i++
+		default:
+			n := strings.IndexAny(expr[i:], wordBoundary)
+			if n < 0 {
+				n = len(expr) - i
+			}
+			if n == 0 {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+			}
+			toks = append(toks, token{tokWord, expr[i : i+n]})
+			i += n
+		}
+	}
+	return toks, nil
+}
+
+func lexQuoted(s string, quote byte) (string, int, error) {
+	var b strings.Builder
+	i := 1
+	for i < len(s) {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) && s[i+1] == quote {
+			b.WriteByte(quote)
+			i += 2
+			continue
+		}
+		if c == quote {
+			return b.String(), i + 1, nil
+		}
+		b.WriteByte(c)
+		i++
+	}
+	return "", 0, fmt.Errorf("unterminated quoted string")
+}
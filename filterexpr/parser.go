@@ -0,0 +1,186 @@
+package filterexpr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.toks) {
+		return token{}, false
+	}
+	return p.toks[p.pos], true
+}
+
+func (p *parser) next() (token, bool) {
+	t, ok := p.peek()
+	if ok {
+		p.pos++
+	}
+	return t, ok
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind != tokOr {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left, right}
+	}
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind != tokAnd {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left, right}
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	if t.kind == tokLParen {
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		closing, ok := p.next()
+		if !ok || closing.kind != tokRParen {
+			return nil, fmt.Errorf("missing closing ')'")
+		}
+		return node, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Node, error) {
+	colTok, ok := p.next()
+	if !ok || colTok.kind != tokWord {
+		return nil, fmt.Errorf("expected column name")
+	}
+	col := colTok.lit
+
+	opTok, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("expected operator after column %q", col)
+	}
+
+	switch opTok.kind {
+	case tokEQ, tokNEQ, tokLT, tokLE, tokGT, tokGE:
+		valTok, err := p.expectValue()
+		if err != nil {
+			return nil, err
+		}
+		return &compareNode{col: col, op: opTok.kind, value: valTok}, nil
+	case tokRegex, tokNotRegex:
+		valTok, err := p.expectValue()
+		if err != nil {
+			return nil, err
+		}
+		re, err := regexp.Compile(valTok)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regexp %q: %w", valTok, err)
+		}
+		return &regexNode{col: col, pattern: re, negate: opTok.kind == tokNotRegex}, nil
+	case tokWord:
+		return p.parseKeywordOp(col, strings.ToLower(opTok.lit))
+	default:
+		return nil, fmt.Errorf("expected operator after column %q, got %q", col, opTok.lit)
+	}
+}
+
+func (p *parser) parseKeywordOp(col, op string) (Node, error) {
+	switch op {
+	case "contains":
+		val, err := p.expectValue()
+		if err != nil {
+			return nil, err
+		}
+		return &containsNode{col: col, value: val, kind: kindContains}, nil
+	case "startswith":
+		val, err := p.expectValue()
+		if err != nil {
+			return nil, err
+		}
+		return &containsNode{col: col, value: val, kind: kindStartsWith}, nil
+	case "endswith":
+		val, err := p.expectValue()
+		if err != nil {
+			return nil, err
+		}
+		return &containsNode{col: col, value: val, kind: kindEndsWith}, nil
+	case "in":
+		values, err := p.parseInList()
+		if err != nil {
+			return nil, err
+		}
+		return &inNode{col: col, values: values}, nil
+	default:
+		return nil, fmt.Errorf("unknown operator %q", op)
+	}
+}
+
+func (p *parser) parseInList() ([]string, error) {
+	open, ok := p.next()
+	if !ok || open.kind != tokLParen {
+		return nil, fmt.Errorf("expected '(' after in")
+	}
+	var values []string
+	for {
+		val, err := p.expectValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, val)
+
+		t, ok := p.next()
+		if !ok {
+			return nil, fmt.Errorf("missing closing ')' in in(...)")
+		}
+		if t.kind == tokRParen {
+			return values, nil
+		}
+		if t.kind != tokComma {
+			return nil, fmt.Errorf("expected ',' or ')' in in(...), got %q", t.lit)
+		}
+	}
+}
+
+func (p *parser) expectValue() (string, error) {
+	t, ok := p.next()
+	if !ok || (t.kind != tokWord && t.kind != tokString) {
+		return "", fmt.Errorf("expected value")
+	}
+	return t.lit, nil
+}
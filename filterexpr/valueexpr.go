@@ -0,0 +1,216 @@
+package filterexpr
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CompiledValue evaluates a value expression against a single CSV row,
+// returning its result as a string.
+type CompiledValue func(row []string) (string, error)
+
+// ValueNode is a parsed value expression, as used by -add to compute new
+// columns: column references, numeric/string literals, +-*/ arithmetic and
+// a handful of functions (upper, lower, trim, len, coalesce, round).
+type ValueNode interface {
+	CompileValue(colIndex map[string]int) (CompiledValue, error)
+}
+
+// ParseValue parses a value expression such as "price*qty" or
+// "upper(region)".
+func ParseValue(expr string) (ValueNode, error) {
+	toks, err := lexValue(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &valueParser{toks: toks}
+	node, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.toks) {
+		return nil, fmt.Errorf("unexpected input after expression: %q", p.toks[p.pos].lit)
+	}
+	return node, nil
+}
+
+type columnNode struct{ name string }
+
+func (n *columnNode) CompileValue(colIndex map[string]int) (CompiledValue, error) {
+	idx, ok := colIndex[n.name]
+	if !ok {
+		return nil, fmt.Errorf("column not found: %s", n.name)
+	}
+	return func(row []string) (string, error) {
+		return valueAt(row, idx), nil
+	}, nil
+}
+
+type literalNode struct{ value string }
+
+func (n *literalNode) CompileValue(map[string]int) (CompiledValue, error) {
+	return func(row []string) (string, error) {
+		return n.value, nil
+	}, nil
+}
+
+type arithNode struct {
+	op          byte
+	left, right ValueNode
+}
+
+func (n *arithNode) CompileValue(colIndex map[string]int) (CompiledValue, error) {
+	left, err := n.left.CompileValue(colIndex)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.CompileValue(colIndex)
+	if err != nil {
+		return nil, err
+	}
+	return func(row []string) (string, error) {
+		lv, err := left(row)
+		if err != nil {
+			return "", err
+		}
+		rv, err := right(row)
+		if err != nil {
+			return "", err
+		}
+		lf, ok := parseFloat(lv)
+		if !ok {
+			return "", fmt.Errorf("non-numeric operand in arithmetic expression: %q", lv)
+		}
+		rf, ok := parseFloat(rv)
+		if !ok {
+			return "", fmt.Errorf("non-numeric operand in arithmetic expression: %q", rv)
+		}
+
+		var result float64
+		switch n.op {
+		case '+':
+			result = lf + rf
+		case '-':
+			result = lf - rf
+		case '*':
+			result = lf * rf
+		case '/':
+			if rf == 0 {
+				return "", fmt.Errorf("division by zero")
+			}
+			result = lf / rf
+		}
+		return formatNumber(result), nil
+	}, nil
+}
+
+type funcNode struct {
+	name string
+	args []ValueNode
+}
+
+func (n *funcNode) CompileValue(colIndex map[string]int) (CompiledValue, error) {
+	compiledArgs := make([]CompiledValue, len(n.args))
+	for i, a := range n.args {
+		c, err := a.CompileValue(colIndex)
+		if err != nil {
+			return nil, err
+		}
+		compiledArgs[i] = c
+	}
+
+	arity := func(want int) error {
+		if len(compiledArgs) != want {
+			return fmt.Errorf("%s(...) expects %d argument(s), got %d", n.name, want, len(compiledArgs))
+		}
+		return nil
+	}
+
+	switch n.name {
+	case "upper":
+		if err := arity(1); err != nil {
+			return nil, err
+		}
+		return func(row []string) (string, error) {
+			v, err := compiledArgs[0](row)
+			return strings.ToUpper(v), err
+		}, nil
+	case "lower":
+		if err := arity(1); err != nil {
+			return nil, err
+		}
+		return func(row []string) (string, error) {
+			v, err := compiledArgs[0](row)
+			return strings.ToLower(v), err
+		}, nil
+	case "trim":
+		if err := arity(1); err != nil {
+			return nil, err
+		}
+		return func(row []string) (string, error) {
+			v, err := compiledArgs[0](row)
+			return strings.TrimSpace(v), err
+		}, nil
+	case "len":
+		if err := arity(1); err != nil {
+			return nil, err
+		}
+		return func(row []string) (string, error) {
+			v, err := compiledArgs[0](row)
+			if err != nil {
+				return "", err
+			}
+			return strconv.Itoa(len(v)), nil
+		}, nil
+	case "coalesce":
+		if len(compiledArgs) == 0 {
+			return nil, fmt.Errorf("coalesce(...) expects at least 1 argument")
+		}
+		return func(row []string) (string, error) {
+			for _, c := range compiledArgs {
+				v, err := c(row)
+				if err != nil {
+					return "", err
+				}
+				if v != "" {
+					return v, nil
+				}
+			}
+			return "", nil
+		}, nil
+	case "round":
+		if err := arity(2); err != nil {
+			return nil, err
+		}
+		return func(row []string) (string, error) {
+			v, err := compiledArgs[0](row)
+			if err != nil {
+				return "", err
+			}
+			f, ok := parseFloat(v)
+			if !ok {
+				return "", fmt.Errorf("round(...) expects a numeric first argument, got %q", v)
+			}
+			nStr, err := compiledArgs[1](row)
+			if err != nil {
+				return "", err
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(nStr))
+			if err != nil {
+				return "", fmt.Errorf("round(...) expects an integer second argument, got %q", nStr)
+			}
+			scale := math.Pow(10, float64(n))
+			return formatNumber(math.Round(f*scale) / scale), nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown function: %s", n.name)
+	}
+}
+
+// formatNumber renders a computed float using the shortest representation
+// that round-trips, without scientific notation.
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,212 @@
+package filterexpr
+
+import (
+	"fmt"
+	"strings"
+)
+
+type vtokenKind int
+
+const (
+	vtWord vtokenKind = iota
+	vtString
+	vtPlus
+	vtMinus
+	vtStar
+	vtSlash
+	vtLParen
+	vtRParen
+	vtComma
+)
+
+type vtoken struct {
+	kind vtokenKind
+	lit  string
+}
+
+func lexValue(expr string) ([]vtoken, error) {
+	var toks []vtoken
+	i := 0
+	for i < len(expr) {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '+':
+			toks = append(toks, vtoken{vtPlus, "+"})
+			i++
+		case c == '-':
+			toks = append(toks, vtoken{vtMinus, "-"})
+			i++
+		case c == '*':
+			toks = append(toks, vtoken{vtStar, "*"})
+			i++
+		case c == '/':
+			toks = append(toks, vtoken{vtSlash, "/"})
+			i++
+		case c == '(':
+			toks = append(toks, vtoken{vtLParen, "("})
+			i++
+		case c == ')':
+			toks = append(toks, vtoken{vtRParen, ")"})
+			i++
+		case c == ',':
+			toks = append(toks, vtoken{vtComma, ","})
+			i++
+		case c == '\'' || c == '"':
+			s, n, err := lexQuoted(expr[i:], c)
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, vtoken{vtString, s})
+			i += n
+		default:
+			n := strings.IndexAny(expr[i:], "+-*/(), \t\n\r'\"")
+			if n < 0 {
+				n = len(expr) - i
+			}
+			if n == 0 {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+			}
+			toks = append(toks, vtoken{vtWord, expr[i : i+n]})
+			i += n
+		}
+	}
+	return toks, nil
+}
+
+type valueParser struct {
+	toks []vtoken
+	pos  int
+}
+
+func (p *valueParser) peek() (vtoken, bool) {
+	if p.pos >= len(p.toks) {
+		return vtoken{}, false
+	}
+	return p.toks[p.pos], true
+}
+
+func (p *valueParser) next() (vtoken, bool) {
+	t, ok := p.peek()
+	if ok {
+		p.pos++
+	}
+	return t, ok
+}
+
+func (p *valueParser) parseExpr() (ValueNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || (t.kind != vtPlus && t.kind != vtMinus) {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		op := byte('+')
+		if t.kind == vtMinus {
+			op = '-'
+		}
+		left = &arithNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *valueParser) parseTerm() (ValueNode, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || (t.kind != vtStar && t.kind != vtSlash) {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		op := byte('*')
+		if t.kind == vtSlash {
+			op = '/'
+		}
+		left = &arithNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *valueParser) parseFactor() (ValueNode, error) {
+	t, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	switch t.kind {
+	case vtString:
+		return &literalNode{value: t.lit}, nil
+	case vtMinus:
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return &arithNode{op: '-', left: &literalNode{value: "0"}, right: operand}, nil
+	case vtLParen:
+		node, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		closing, ok := p.next()
+		if !ok || closing.kind != vtRParen {
+			return nil, fmt.Errorf("missing closing ')'")
+		}
+		return node, nil
+	case vtWord:
+		if _, ok := parseFloat(t.lit); ok {
+			return &literalNode{value: t.lit}, nil
+		}
+		if next, ok := p.peek(); ok && next.kind == vtLParen {
+			p.pos++
+			args, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			return &funcNode{name: strings.ToLower(t.lit), args: args}, nil
+		}
+		return &columnNode{name: t.lit}, nil
+	default:
+		return nil, fmt.Errorf("unexpected token %q", t.lit)
+	}
+}
+
+func (p *valueParser) parseArgs() ([]ValueNode, error) {
+	if t, ok := p.peek(); ok && t.kind == vtRParen {
+		p.pos++
+		return nil, nil
+	}
+
+	var args []ValueNode
+	for {
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		t, ok := p.next()
+		if !ok {
+			return nil, fmt.Errorf("missing closing ')'")
+		}
+		if t.kind == vtRParen {
+			return args, nil
+		}
+		if t.kind != vtComma {
+			return nil, fmt.Errorf("expected ',' or ')', got %q", t.lit)
+		}
+	}
+}
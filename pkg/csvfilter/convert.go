@@ -0,0 +1,132 @@
+package csvfilter
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// decode builds a T from row, using fields to map each tagged field to its
+// CSV column and convert the raw string per the field's kind.
+func decode[T any](fields []fieldSpec, colIndex map[string]int, row []string) (T, error) {
+	var v T
+	rv := reflect.ValueOf(&v).Elem()
+
+	for _, f := range fields {
+		idx, ok := colIndex[f.name]
+		if !ok {
+			return v, fmt.Errorf("csvfilter: column not found: %s", f.name)
+		}
+		raw := ""
+		if idx < len(row) {
+			raw = row[idx]
+		}
+		if raw == "" && f.required {
+			return v, fmt.Errorf("csvfilter: column %s: required but empty", f.name)
+		}
+
+		if err := decodeField(rv.Field(f.index), f, raw); err != nil {
+			return v, fmt.Errorf("csvfilter: column %s: %w", f.name, err)
+		}
+	}
+
+	return v, nil
+}
+
+func decodeField(field reflect.Value, f fieldSpec, raw string) error {
+	if raw == "" {
+		return nil
+	}
+
+	if field.Type() == timeType {
+		layout := f.timeLayout
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		t, err := time.Parse(layout, raw)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported field kind: %s", field.Kind())
+	}
+
+	return nil
+}
+
+// encode builds a CSV row from v, converting each tagged field to a string.
+// A zero-valued field tagged `,omitempty` is written as an empty string.
+func encode[T any](fields []fieldSpec, v T) ([]string, error) {
+	rv := reflect.ValueOf(v)
+	row := make([]string, len(fields))
+
+	for i, f := range fields {
+		field := rv.Field(f.index)
+		if f.omitempty && field.IsZero() {
+			continue
+		}
+
+		s, err := encodeField(field, f)
+		if err != nil {
+			return nil, fmt.Errorf("csvfilter: column %s: %w", f.name, err)
+		}
+		row[i] = s
+	}
+
+	return row, nil
+}
+
+func encodeField(field reflect.Value, f fieldSpec) (string, error) {
+	if field.Type() == timeType {
+		t := field.Interface().(time.Time)
+		if t.IsZero() {
+			return "", nil
+		}
+		layout := f.timeLayout
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return t.Format(layout), nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		return field.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(field.Int(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(field.Float(), 'f', -1, 64), nil
+	case reflect.Bool:
+		return strconv.FormatBool(field.Bool()), nil
+	default:
+		return "", fmt.Errorf("unsupported field kind: %s", field.Kind())
+	}
+}
@@ -0,0 +1,355 @@
+// Package csvfilter is a reusable, struct-tag based API for csvfilter's
+// filter and sort pipeline, for Go programs that want the same filtering
+// and multi-key sorting the CLI provides without shelling out. Rows are
+// decoded into a caller-supplied struct type via `csv:"colname"` tags.
+package csvfilter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+
+	"github.com/zsuzuki/csvfilter/extsort"
+	"github.com/zsuzuki/csvfilter/filterexpr"
+	"github.com/zsuzuki/csvfilter/project"
+	"github.com/zsuzuki/csvfilter/rowsort"
+	"github.com/zsuzuki/csvfilter/schema"
+)
+
+// DefaultSortBuffer is how many rows Filter buffers in memory before a Sort
+// spills to disk as an external merge sort, when Options.SortBuffer is unset.
+const DefaultSortBuffer = 500000
+
+// Options configures Filter's filter, projection and sort behavior. The
+// zero value reads every row in file order.
+type Options struct {
+	// Where is a list of filterexpr predicates, e.g. "price>=100", ANDed
+	// together by default or ORed when Or is set. Columns are referenced by
+	// their csv tag name, not the struct field name.
+	Where []string
+	// ExtraWhere holds already-built filterexpr nodes, combined with Where
+	// the same way: ANDed by default or ORed when Or is set. For a caller
+	// with a predicate it built directly rather than as text — e.g.
+	// cmd/csvfilter's legacy -filter/-value sugar, built via
+	// filterexpr.Contains so an arbitrary column name never has to survive
+	// a round trip through the expression parser.
+	ExtraWhere []filterexpr.Node
+	// Or combines Where/ExtraWhere with OR instead of AND.
+	Or bool
+	// Schema declares typed columns, using the same syntax as the CLI's
+	// -schema flag, e.g. "price:num,qty:int". Declared columns are
+	// validated on every row, and a numeric column lets Where/Sort skip
+	// probing whether each value parses as a number.
+	Schema string
+	// Add, Select and Drop project the row between Where filtering and
+	// Sort, the same way the CLI's -add/-select/-drop flags do, so Sort can
+	// reference either an original or a newly-added column name.
+	Add    []string
+	Select string
+	Drop   string
+	// Sort is a comma-separated multi-key sort spec, e.g.
+	// "region:asc:str,price:desc:num", using the same syntax as the CLI's
+	// -sort flag and referencing columns by their post-projection name.
+	Sort string
+	// SortFallback is consulted when Sort names a single column with no
+	// ":dir:mode" suffix, mirroring the CLI's separate -type flag.
+	SortFallback string
+	// SortBuffer caps how many rows are buffered in memory before Sort
+	// spills to disk as an external merge sort. Zero uses DefaultSortBuffer.
+	SortBuffer int
+}
+
+// Filter reads CSV rows from r and yields them filtered by Options.Where/Or,
+// projected by Options.Add/Select/Drop and ordered by Options.Sort.
+//
+// If T is []string, rows are yielded as-is instead of being decoded, for
+// callers whose rows don't fit a fixed struct shape (e.g. columns picked at
+// runtime). Otherwise each row is decoded into a T per its `csv` struct
+// tags; a field tagged `,required` whose column is empty, or any column
+// referenced by Where/Sort that isn't present in the header, yields an
+// error.
+func Filter[T any](r io.Reader, opts Options) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		csvr := csv.NewReader(r)
+		headers, err := csvr.Read()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			yield(zero[T](), err)
+			return
+		}
+
+		_, rows, err := FilterRows[T](headers, csvr.Read, opts)
+		if err != nil {
+			yield(zero[T](), err)
+			return
+		}
+		for v, err := range rows {
+			if !yield(v, err) {
+				return
+			}
+		}
+	}
+}
+
+// FilterRows is the row-source form of Filter, for a caller that already
+// produces decoded CSV rows itself instead of handing Filter a raw
+// io.Reader — e.g. cmd/csvfilter, where csvio's encoding, delimiter,
+// comment and header handling runs upstream of everything here. inHeaders
+// names the columns next's rows are indexed by; next returns io.EOF once
+// exhausted.
+//
+// Where, Schema and Sort are resolved against inHeaders; Add/Select/Drop
+// then project each row between filtering and sorting, so Sort is resolved
+// against the projected column names, the same order cmd/csvfilter's own
+// pipeline runs in. outHeaders (the names after any projection) and any
+// compile error are returned eagerly, before the row sequence, so a caller
+// can write its own header row ahead of the data rows.
+func FilterRows[T any](inHeaders []string, next func() ([]string, error), opts Options) (outHeaders []string, rows iter.Seq2[T, error], err error) {
+	colIndex := indexByHeader(inHeaders)
+
+	var sc schema.Schema
+	if opts.Schema != "" {
+		sc, err = schema.Parse(opts.Schema)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	filterEval, err := CompileFilter(opts, colIndex, sc.IsNumeric)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	outHeaders, outColIndex, transform, err := project.Compile(inHeaders, colIndex, project.Options{
+		Add:    opts.Add,
+		Select: opts.Select,
+		Drop:   opts.Drop,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys, err := CompileSort(opts, outColIndex, sc.IsNumeric)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rawMode, fields, err := rowFields[T]()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filteredNext := func() ([]string, error) {
+		for {
+			row, err := next()
+			if err != nil {
+				return nil, err
+			}
+			if sc != nil {
+				if err := sc.ValidateRow(colIndex, row); err != nil {
+					return nil, err
+				}
+			}
+			if filterEval != nil {
+				ok, err := filterEval(row)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					continue
+				}
+			}
+			out, err := transform(row)
+			if err != nil {
+				return nil, err
+			}
+			if err := rowsort.ValidateNumericRow(out, keys); err != nil {
+				return nil, err
+			}
+			return out, nil
+		}
+	}
+
+	seq := func(yield func(T, error) bool) {
+		emit := func(row []string) error {
+			v, err := decodeRow[T](rawMode, fields, outColIndex, row)
+			if err != nil {
+				return err
+			}
+			if !yield(v, nil) {
+				return errStop
+			}
+			return nil
+		}
+
+		var runErr error
+		if len(keys) == 0 {
+			runErr = drain(filteredNext, emit)
+		} else {
+			bufferRows := opts.SortBuffer
+			if bufferRows <= 0 {
+				bufferRows = DefaultSortBuffer
+			}
+			runErr = extsort.Run(filteredNext, keys, bufferRows, emit)
+		}
+
+		if runErr != nil && runErr != errStop && runErr != io.EOF {
+			yield(zero[T](), runErr)
+		}
+	}
+
+	return outHeaders, seq, nil
+}
+
+// isRawRows reports whether T is []string, letting Filter/Write bypass
+// struct-tag decoding entirely and pass CSV rows through unchanged.
+func isRawRows[T any]() bool {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String
+}
+
+// rowFields reports whether T is raw-row mode and, if not, resolves its
+// `csv` struct tags. Shared by Filter and Write so both treat T the same
+// way.
+func rowFields[T any]() (rawMode bool, fields []fieldSpec, err error) {
+	if isRawRows[T]() {
+		return true, nil, nil
+	}
+	fields, err = structFields[T]()
+	return false, fields, err
+}
+
+// decodeRow returns row as-is in raw mode (asserting T is []string), or
+// decodes it into a T per fields/colIndex otherwise.
+func decodeRow[T any](rawMode bool, fields []fieldSpec, colIndex map[string]int, row []string) (T, error) {
+	if rawMode {
+		v, ok := any(row).(T)
+		if !ok {
+			return zero[T](), fmt.Errorf("csvfilter: %T is not []string", zero[T]())
+		}
+		return v, nil
+	}
+	return decode[T](fields, colIndex, row)
+}
+
+// errStop unwinds drain/extsort.Run when the caller's yield has already
+// returned false, without being reported as a decoding or I/O error.
+var errStop = fmt.Errorf("csvfilter: iteration stopped")
+
+func drain(next func() ([]string, error), emit func(row []string) error) error {
+	for {
+		row, err := next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := emit(row); err != nil {
+			return err
+		}
+	}
+}
+
+// CompileFilter compiles opts.Where and opts.ExtraWhere (ANDed, or ORed when
+// opts.Or is set) against colIndex into a row matcher. isNumeric, if
+// non-nil, is consulted the same way filterexpr.Node.Compile uses it.
+// Returns a nil Compiled and nil error if both are empty. Exported so other
+// packages building their own row pipeline around filterexpr can reuse this
+// compilation step.
+func CompileFilter(opts Options, colIndex map[string]int, isNumeric func(col string) bool) (filterexpr.Compiled, error) {
+	var nodes []filterexpr.Node
+	for _, w := range opts.Where {
+		node, err := filterexpr.Parse(w)
+		if err != nil {
+			return nil, fmt.Errorf("invalid where %q: %w", w, err)
+		}
+		nodes = append(nodes, node)
+	}
+	nodes = append(nodes, opts.ExtraWhere...)
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+
+	var combined filterexpr.Node
+	if opts.Or {
+		combined = filterexpr.Or(nodes...)
+	} else {
+		combined = filterexpr.And(nodes...)
+	}
+	return combined.Compile(colIndex, isNumeric)
+}
+
+// CompileSort parses opts.Sort into resolved sort keys against colIndex,
+// consulting isNumeric for any key left in auto mode. Returns nil keys and
+// a nil error if opts.Sort is empty. Exported for the same reason as
+// CompileFilter.
+func CompileSort(opts Options, colIndex map[string]int, isNumeric func(col string) bool) ([]rowsort.Key, error) {
+	if opts.Sort == "" {
+		return nil, nil
+	}
+	return rowsort.ParseKeys(opts.Sort, opts.SortFallback, colIndex, isNumeric)
+}
+
+func indexByHeader(headers []string) map[string]int {
+	m := make(map[string]int, len(headers))
+	for i, h := range headers {
+		m[h] = i
+	}
+	return m
+}
+
+func zero[T any]() T {
+	var z T
+	return z
+}
+
+// Write writes rows to w as CSV. If T is []string, each row is written
+// as-is with no header row (the caller owns the header, as cmd/csvfilter
+// does). Otherwise a header row is built from T's `csv` struct tags in
+// field declaration order, and each row is encoded per those tags.
+func Write[T any](w io.Writer, rows iter.Seq[T]) error {
+	rawMode, fields, err := rowFields[T]()
+	if err != nil {
+		return err
+	}
+
+	csvw := csv.NewWriter(w)
+
+	if !rawMode {
+		header := make([]string, len(fields))
+		for i, f := range fields {
+			header[i] = f.name
+		}
+		if err := csvw.Write(header); err != nil {
+			return err
+		}
+	}
+
+	for v := range rows {
+		var row []string
+		if rawMode {
+			raw, ok := any(v).([]string)
+			if !ok {
+				return fmt.Errorf("csvfilter: %T is not []string", v)
+			}
+			row = raw
+		} else {
+			var err error
+			row, err = encode(fields, v)
+			if err != nil {
+				return err
+			}
+		}
+		if err := csvw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	csvw.Flush()
+	return csvw.Error()
+}
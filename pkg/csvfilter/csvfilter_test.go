@@ -0,0 +1,135 @@
+package csvfilter
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type person struct {
+	Name    string    `csv:"name"`
+	Age     int       `csv:"age"`
+	Email   string    `csv:"email,omitempty"`
+	Joined  time.Time `csv:"joined,parse=2006-01-02"`
+	Country string    `csv:"country,required"`
+}
+
+func TestFilterDecodesStructTags(t *testing.T) {
+	input := "name,age,email,joined,country\n" +
+		"Alice,30,alice@example.com,2020-01-15,US\n" +
+		"Bob,25,,2021-06-01,CA\n"
+
+	var got []person
+	for v, err := range Filter[person](strings.NewReader(input), Options{}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, v)
+	}
+
+	want := []person{
+		{Name: "Alice", Age: 30, Email: "alice@example.com", Joined: time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC), Country: "US"},
+		{Name: "Bob", Age: 25, Email: "", Joined: time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC), Country: "CA"},
+	}
+	if !slices.Equal(joinedStrings(got), joinedStrings(want)) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func joinedStrings(rows []person) []string {
+	out := make([]string, len(rows))
+	for i, r := range rows {
+		out[i] = r.Name + "|" + r.Email + "|" + r.Joined.Format("2006-01-02") + "|" + r.Country
+	}
+	return out
+}
+
+func TestFilterRequiredColumnEmpty(t *testing.T) {
+	input := "name,age,email,joined,country\nAlice,30,,2020-01-15,\n"
+
+	var gotErr error
+	for _, err := range Filter[person](strings.NewReader(input), Options{}) {
+		gotErr = err
+		break
+	}
+	if gotErr == nil {
+		t.Fatal("expected an error for an empty required column, got nil")
+	}
+}
+
+func TestFilterWhereAndSort(t *testing.T) {
+	input := "name,age,email,joined,country\n" +
+		"Alice,30,a@x.com,2020-01-15,US\n" +
+		"Bob,25,b@x.com,2021-06-01,CA\n" +
+		"Carol,40,c@x.com,2019-03-03,US\n"
+
+	var got []string
+	for v, err := range Filter[person](strings.NewReader(input), Options{
+		Where: []string{"country=US"},
+		Sort:  "age:asc:num",
+	}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, v.Name)
+	}
+
+	want := []string{"Alice", "Carol"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestFilterRawRowsBypassesStructDecoding(t *testing.T) {
+	input := "name,age\nAlice,30\nBob,25\n"
+
+	var got [][]string
+	for row, err := range Filter[[]string](strings.NewReader(input), Options{Sort: "age:desc:num"}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, row)
+	}
+
+	want := [][]string{{"Alice", "30"}, {"Bob", "25"}}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if !slices.Equal(got[i], want[i]) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestWriteStructTags(t *testing.T) {
+	rows := []person{
+		{Name: "Alice", Age: 30, Country: "US"},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, slices.Values(rows)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "name,age,email,joined,country\nAlice,30,,,US\n"
+	if buf.String() != want {
+		t.Fatalf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWriteRawRowsHasNoHeader(t *testing.T) {
+	rows := [][]string{{"Alice", "30"}, {"Bob", "25"}}
+
+	var buf bytes.Buffer
+	if err := Write[[]string](&buf, slices.Values(rows)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "Alice,30\nBob,25\n"
+	if buf.String() != want {
+		t.Fatalf("got %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,84 @@
+package csvfilter
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// fieldCache memoizes structFields per reflect.Type, since struct tags never
+// change at runtime and Filter/Write may be called once per row's type in a
+// hot loop.
+var fieldCache sync.Map
+
+// fieldSpec is one T field's resolved `csv` tag: which CSV column it reads
+// from/writes to, and how to decode it.
+type fieldSpec struct {
+	index      int
+	name       string
+	required   bool
+	omitempty  bool
+	timeLayout string
+}
+
+// structFields reflects T's fields for `csv:"..."` tags. A field with no
+// csv tag, or tagged `csv:"-"`, is skipped. The result is cached per type.
+func structFields[T any]() ([]fieldSpec, error) {
+	var zero T
+	t := reflect.TypeOf(zero)
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("csvfilter: %T is not a struct", zero)
+	}
+
+	if cached, ok := fieldCache.Load(t); ok {
+		return cached.([]fieldSpec), nil
+	}
+
+	var fields []fieldSpec
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("csv")
+		if !ok || tag == "-" {
+			continue
+		}
+
+		spec, err := parseTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("csvfilter: field %s: %w", f.Name, err)
+		}
+		if spec.timeLayout != "" && f.Type != timeType {
+			return nil, fmt.Errorf("csvfilter: field %s: parse= is only valid on time.Time fields", f.Name)
+		}
+		spec.index = i
+		fields = append(fields, spec)
+	}
+
+	fieldCache.Store(t, fields)
+	return fields, nil
+}
+
+func parseTag(tag string) (fieldSpec, error) {
+	parts := strings.Split(tag, ",")
+	spec := fieldSpec{name: strings.TrimSpace(parts[0])}
+
+	for _, opt := range parts[1:] {
+		opt = strings.TrimSpace(opt)
+		switch {
+		case opt == "omitempty":
+			spec.omitempty = true
+		case opt == "required":
+			spec.required = true
+		case strings.HasPrefix(opt, "parse="):
+			spec.timeLayout = strings.TrimPrefix(opt, "parse=")
+		default:
+			return fieldSpec{}, fmt.Errorf("unsupported csv tag option %q", opt)
+		}
+	}
+
+	if spec.name == "" {
+		return fieldSpec{}, fmt.Errorf("csv tag has no column name: %q", tag)
+	}
+
+	return spec, nil
+}
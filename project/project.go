@@ -0,0 +1,178 @@
+// Package project implements csvfilter's projection step: computing -add
+// columns and then restricting/renaming/reordering output per -select and
+// -drop. It runs between filtering and sorting, so -sort can reference
+// either original or newly-added column names.
+package project
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zsuzuki/csvfilter/filterexpr"
+)
+
+// Options configures the projection step.
+type Options struct {
+	// Add is a list of "name=expr" computed-column specs, evaluated with
+	// filterexpr's value expression engine and appended in order; later
+	// specs may reference earlier ones.
+	Add []string
+	// Select, if non-empty, restricts and reorders output to these
+	// comma-separated columns, e.g. "name,price,region as r".
+	Select string
+	// Drop removes these comma-separated column names from the output.
+	// Ignored for any column also named by Select.
+	Drop string
+}
+
+// Compile resolves Options against headers/colIndex once, returning the
+// projected header row, a matching column index, and a per-row transform
+// function. This lets a row-at-a-time pipeline project each row as it's
+// read instead of materializing the whole table.
+func Compile(headers []string, colIndex map[string]int, opts Options) ([]string, map[string]int, func(row []string) ([]string, error), error) {
+	if len(opts.Add) == 0 && opts.Select == "" && opts.Drop == "" {
+		identity := func(row []string) ([]string, error) { return row, nil }
+		return headers, colIndex, identity, nil
+	}
+
+	workingHeaders := append([]string(nil), headers...)
+	workingIndex := make(map[string]int, len(colIndex))
+	for k, v := range colIndex {
+		workingIndex[k] = v
+	}
+
+	var addFns []filterexpr.CompiledValue
+	for _, spec := range opts.Add {
+		name, expr, err := splitAddSpec(spec)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		node, err := filterexpr.ParseValue(expr)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid -add %q: %w", spec, err)
+		}
+		compiled, err := node.CompileValue(workingIndex)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid -add %q: %w", spec, err)
+		}
+
+		workingIndex[name] = len(workingHeaders)
+		workingHeaders = append(workingHeaders, name)
+		addFns = append(addFns, compiled)
+	}
+
+	cols := resolveColumns(workingHeaders, opts)
+
+	outHeaders := make([]string, len(cols))
+	outIdx := make([]int, len(cols))
+	for i, c := range cols {
+		idx, ok := workingIndex[c.src]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("column not found: %s", c.src)
+		}
+		outHeaders[i] = c.out
+		outIdx[i] = idx
+	}
+
+	adds := opts.Add
+	transform := func(row []string) ([]string, error) {
+		extended := row
+		if len(adds) > 0 {
+			extended = append(append([]string(nil), row...), make([]string, len(adds))...)
+			for i, fn := range addFns {
+				val, err := fn(extended)
+				if err != nil {
+					return nil, fmt.Errorf("-add %q: %w", adds[i], err)
+				}
+				extended[len(row)+i] = val
+			}
+		}
+
+		outRow := make([]string, len(outIdx))
+		for i, idx := range outIdx {
+			outRow[i] = valueAt(extended, idx)
+		}
+		return outRow, nil
+	}
+
+	return outHeaders, indexByHeader(outHeaders), transform, nil
+}
+
+type colRef struct{ src, out string }
+
+// resolveColumns decides the final output column list: -select restricts
+// and reorders (with optional "as alias"); otherwise every current header
+// is kept. -drop then removes any named columns from that list, unless
+// -select was used — a column the user explicitly asked to keep via
+// -select is never removed by -drop.
+func resolveColumns(headers []string, opts Options) []colRef {
+	var cols []colRef
+	if opts.Select != "" {
+		return parseSelectSpec(opts.Select)
+	}
+	for _, h := range headers {
+		cols = append(cols, colRef{src: h, out: h})
+	}
+
+	if opts.Drop != "" {
+		drop := make(map[string]bool)
+		for _, name := range splitList(opts.Drop) {
+			drop[name] = true
+		}
+		kept := cols[:0]
+		for _, c := range cols {
+			if !drop[c.src] {
+				kept = append(kept, c)
+			}
+		}
+		cols = kept
+	}
+
+	return cols
+}
+
+func parseSelectSpec(spec string) []colRef {
+	var refs []colRef
+	for _, seg := range splitList(spec) {
+		if idx := strings.Index(strings.ToLower(seg), " as "); idx >= 0 {
+			refs = append(refs, colRef{src: strings.TrimSpace(seg[:idx]), out: strings.TrimSpace(seg[idx+4:])})
+		} else {
+			refs = append(refs, colRef{src: seg, out: seg})
+		}
+	}
+	return refs
+}
+
+func splitList(spec string) []string {
+	var names []string
+	for _, s := range strings.Split(spec, ",") {
+		s = strings.TrimSpace(s)
+		if s != "" {
+			names = append(names, s)
+		}
+	}
+	return names
+}
+
+func splitAddSpec(spec string) (string, string, error) {
+	name, expr, ok := strings.Cut(spec, "=")
+	if !ok {
+		return "", "", fmt.Errorf("invalid -add %q: expected name=expr", spec)
+	}
+	return strings.TrimSpace(name), strings.TrimSpace(expr), nil
+}
+
+func indexByHeader(headers []string) map[string]int {
+	m := make(map[string]int, len(headers))
+	for i, h := range headers {
+		m[h] = i
+	}
+	return m
+}
+
+func valueAt(row []string, idx int) string {
+	if idx < len(row) {
+		return row[idx]
+	}
+	return ""
+}
@@ -0,0 +1,154 @@
+package project
+
+import (
+	"slices"
+	"testing"
+)
+
+func indexOf(headers []string) map[string]int {
+	m := make(map[string]int, len(headers))
+	for i, h := range headers {
+		m[h] = i
+	}
+	return m
+}
+
+func TestCompileIdentityWhenNoOptions(t *testing.T) {
+	headers := []string{"name", "price"}
+	colIndex := indexOf(headers)
+
+	outHeaders, outIdx, transform, err := Compile(headers, colIndex, Options{})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if !slices.Equal(outHeaders, headers) {
+		t.Fatalf("outHeaders = %v, want %v", outHeaders, headers)
+	}
+	row, err := transform([]string{"Alice", "10"})
+	if err != nil {
+		t.Fatalf("transform: %v", err)
+	}
+	if !slices.Equal(row, []string{"Alice", "10"}) {
+		t.Fatalf("row = %v", row)
+	}
+	if outIdx["price"] != 1 {
+		t.Fatalf("outIdx[price] = %d, want 1", outIdx["price"])
+	}
+}
+
+func TestCompileSelectRestrictsReordersAndRenames(t *testing.T) {
+	headers := []string{"name", "price", "region"}
+	colIndex := indexOf(headers)
+
+	outHeaders, _, transform, err := Compile(headers, colIndex, Options{Select: "price,region as r"})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if want := []string{"price", "r"}; !slices.Equal(outHeaders, want) {
+		t.Fatalf("outHeaders = %v, want %v", outHeaders, want)
+	}
+	row, err := transform([]string{"Alice", "10", "JP"})
+	if err != nil {
+		t.Fatalf("transform: %v", err)
+	}
+	if want := []string{"10", "JP"}; !slices.Equal(row, want) {
+		t.Fatalf("row = %v, want %v", row, want)
+	}
+}
+
+func TestCompileDropRemovesColumn(t *testing.T) {
+	headers := []string{"name", "price", "internal_id"}
+	colIndex := indexOf(headers)
+
+	outHeaders, _, transform, err := Compile(headers, colIndex, Options{Drop: "internal_id"})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if want := []string{"name", "price"}; !slices.Equal(outHeaders, want) {
+		t.Fatalf("outHeaders = %v, want %v", outHeaders, want)
+	}
+	row, err := transform([]string{"Alice", "10", "xyz"})
+	if err != nil {
+		t.Fatalf("transform: %v", err)
+	}
+	if want := []string{"Alice", "10"}; !slices.Equal(row, want) {
+		t.Fatalf("row = %v, want %v", row, want)
+	}
+}
+
+// TestCompileDropIgnoresColumnsNamedBySelect guards the Options.Drop doc
+// contract ("Ignored for any column also named by Select"): -drop must not
+// silently remove a column the user explicitly asked to keep via -select.
+func TestCompileDropIgnoresColumnsNamedBySelect(t *testing.T) {
+	headers := []string{"name", "price"}
+	colIndex := indexOf(headers)
+
+	outHeaders, _, _, err := Compile(headers, colIndex, Options{Select: "name,price", Drop: "price"})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if want := []string{"name", "price"}; !slices.Equal(outHeaders, want) {
+		t.Fatalf("outHeaders = %v, want %v (Drop must be ignored when Select names the column)", outHeaders, want)
+	}
+}
+
+func TestCompileAddComputedColumn(t *testing.T) {
+	headers := []string{"price", "qty"}
+	colIndex := indexOf(headers)
+
+	outHeaders, _, transform, err := Compile(headers, colIndex, Options{Add: []string{"total=price*qty"}})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if want := []string{"price", "qty", "total"}; !slices.Equal(outHeaders, want) {
+		t.Fatalf("outHeaders = %v, want %v", outHeaders, want)
+	}
+	row, err := transform([]string{"10", "3"})
+	if err != nil {
+		t.Fatalf("transform: %v", err)
+	}
+	if want := []string{"10", "3", "30"}; !slices.Equal(row, want) {
+		t.Fatalf("row = %v, want %v", row, want)
+	}
+}
+
+func TestCompileAddThenSelectCanReferenceNewColumn(t *testing.T) {
+	headers := []string{"price", "qty"}
+	colIndex := indexOf(headers)
+
+	outHeaders, _, transform, err := Compile(headers, colIndex, Options{
+		Add:    []string{"total=price*qty"},
+		Select: "total",
+	})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if want := []string{"total"}; !slices.Equal(outHeaders, want) {
+		t.Fatalf("outHeaders = %v, want %v", outHeaders, want)
+	}
+	row, err := transform([]string{"10", "3"})
+	if err != nil {
+		t.Fatalf("transform: %v", err)
+	}
+	if want := []string{"30"}; !slices.Equal(row, want) {
+		t.Fatalf("row = %v, want %v", row, want)
+	}
+}
+
+func TestCompileSelectUnknownColumnErrors(t *testing.T) {
+	headers := []string{"name"}
+	colIndex := indexOf(headers)
+
+	if _, _, _, err := Compile(headers, colIndex, Options{Select: "missing"}); err == nil {
+		t.Fatal("expected an error selecting an unknown column")
+	}
+}
+
+func TestCompileInvalidAddSpecErrors(t *testing.T) {
+	headers := []string{"name"}
+	colIndex := indexOf(headers)
+
+	if _, _, _, err := Compile(headers, colIndex, Options{Add: []string{"noequals"}}); err == nil {
+		t.Fatal("expected an error for an -add spec missing '='")
+	}
+}
@@ -0,0 +1,273 @@
+// Package rowsort implements csvfilter's multi-key row comparator: parsing
+// a -sort spec into resolved keys and comparing two rows against them. It's
+// shared by the in-memory sort path and extsort's external merge sort so
+// both order rows identically.
+package rowsort
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Mode is how a key's values are compared.
+type Mode int
+
+const (
+	ModeAuto Mode = iota
+	ModeNum
+	ModeStr
+)
+
+// Key is one resolved sort key: the column index to compare, its direction
+// (1 asc, -1 desc), and its comparison mode.
+type Key struct {
+	Idx  int
+	Dir  int
+	Mode Mode
+}
+
+// ParseKeys parses a -sort spec into resolved keys. A spec is one or more
+// comma-separated "col[:dir[:mode]]" segments, e.g.
+// "region:asc:str,price:desc:num,date:asc". For backward compatibility, a
+// single plain column name (no colon) falls back to fallbackType (the
+// separate -type flag) for its direction and mode.
+//
+// isNumeric, if non-nil, is consulted for any key left in ModeAuto: when it
+// reports a column numeric (e.g. from a -schema declaration), the key is
+// sorted numerically without probing each row's value.
+func ParseKeys(spec, fallbackType string, colIndex map[string]int, isNumeric func(col string) bool) ([]Key, error) {
+	segments := strings.Split(spec, ",")
+
+	keys := make([]Key, 0, len(segments))
+	for _, seg := range segments {
+		seg = strings.TrimSpace(seg)
+		if seg == "" {
+			continue
+		}
+
+		parts := strings.SplitN(seg, ":", 3)
+		col := strings.TrimSpace(parts[0])
+
+		typeSpec := ""
+		if len(parts) >= 2 {
+			typeSpec = strings.TrimSpace(parts[1])
+			if len(parts) >= 3 {
+				typeSpec += ":" + strings.TrimSpace(parts[2])
+			}
+		} else if len(segments) == 1 {
+			typeSpec = fallbackType
+		}
+
+		direction, mode, err := normalizeSortType(typeSpec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sort spec %q: %w", seg, err)
+		}
+
+		idx, ok := colIndex[col]
+		if !ok {
+			return nil, fmt.Errorf("sort column not found: %s", col)
+		}
+		if mode == ModeAuto && isNumeric != nil && isNumeric(col) {
+			mode = ModeNum
+		}
+
+		keys = append(keys, Key{Idx: idx, Dir: direction, Mode: mode})
+	}
+
+	return keys, nil
+}
+
+func normalizeSortType(t string) (int, Mode, error) {
+	trimmed := strings.ToLower(strings.TrimSpace(t))
+	base := trimmed
+	mode := ModeAuto
+	if parts := strings.SplitN(trimmed, ":", 2); len(parts) == 2 {
+		base = strings.TrimSpace(parts[0])
+		switch strings.TrimSpace(parts[1]) {
+		case "", "auto":
+			mode = ModeAuto
+		case "num", "number", "numeric":
+			mode = ModeNum
+		case "str", "string", "text":
+			mode = ModeStr
+		default:
+			return 0, ModeAuto, fmt.Errorf("unsupported sort mode: %s", parts[1])
+		}
+	}
+
+	switch base {
+	case "", "asc", "lt", "le":
+		return 1, mode, nil
+	case "desc", "gt", "ge":
+		return -1, mode, nil
+	default:
+		return 0, ModeAuto, fmt.Errorf("unsupported sort type: %s", t)
+	}
+}
+
+// ValidateNumeric checks that every row's value at a ModeNum key parses as
+// a number, so a malformed value is reported before the sort runs rather
+// than silently falling back to lexical order mid-sort.
+func ValidateNumeric(rows [][]string, keys []Key) error {
+	for _, row := range rows {
+		if err := ValidateNumericRow(row, keys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateNumericRow checks row's value at each ModeNum key, so a streaming
+// caller can validate each row as it's read instead of buffering the whole
+// table first.
+func ValidateNumericRow(row []string, keys []Key) error {
+	for _, k := range keys {
+		if k.Mode != ModeNum {
+			continue
+		}
+		v := strings.TrimSpace(valueAt(row, k.Idx))
+		if v == "" {
+			return fmt.Errorf("numeric sort requested but empty value found")
+		}
+		if _, ok := parseFloat(v); !ok {
+			return fmt.Errorf("numeric sort requested but non-numeric value found: %s", v)
+		}
+	}
+	return nil
+}
+
+// Compare orders two rows against keys, returning -1, 0 or 1, falling
+// through to the next key on a tie.
+func Compare(a, b []string, keys []Key) int {
+	for _, k := range keys {
+		c := compareValues(valueAt(a, k.Idx), valueAt(b, k.Idx), k.Mode)
+		if c == 0 {
+			continue
+		}
+		if k.Dir < 0 {
+			c = -c
+		}
+		return c
+	}
+	return 0
+}
+
+// Less reports whether a sorts before b.
+func Less(a, b []string, keys []Key) bool {
+	return Compare(a, b, keys) < 0
+}
+
+// compareValues orders two cell values for the given mode, returning -1, 0
+// or 1. Numeric comparison is used whenever both values parse as floats and
+// mode isn't forced to string; otherwise comparison is lexical.
+func compareValues(a, b string, mode Mode) int {
+	if mode != ModeStr {
+		if fa, okA := parseFloat(a); okA {
+			if fb, okB := parseFloat(b); okB {
+				switch {
+				case fa < fb:
+					return -1
+				case fa > fb:
+					return 1
+				default:
+					return 0
+				}
+			}
+		}
+	}
+
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func valueAt(row []string, idx int) string {
+	if idx < len(row) {
+		return row[idx]
+	}
+	return ""
+}
+
+func parseFloat(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
+
+// KeyValue is a row's precomputed value at a single sort key, as returned by
+// Precompute and consumed by CompareCached. Exported so callers that manage
+// their own row storage (e.g. extsort's spilled runs) can precompute once
+// and reuse the cache across repeated comparisons, the same way Sort does.
+type KeyValue struct {
+	F     float64
+	Valid bool
+}
+
+// Precompute parses row's value at each key once, for reuse by CompareCached
+// across the many pairwise comparisons a sort makes over the same rows.
+func Precompute(rows [][]string, keys []Key) [][]KeyValue {
+	cache := make([][]KeyValue, len(rows))
+	for i, row := range rows {
+		cache[i] = precomputeRow(row, keys)
+	}
+	return cache
+}
+
+func precomputeRow(row []string, keys []Key) []KeyValue {
+	vals := make([]KeyValue, len(keys))
+	for i, k := range keys {
+		if k.Mode == ModeStr {
+			continue
+		}
+		f, ok := parseFloat(valueAt(row, k.Idx))
+		vals[i] = KeyValue{F: f, Valid: ok}
+	}
+	return vals
+}
+
+// CompareCached is equivalent to Compare, but reuses ca/cb (the precomputed
+// values for a and b returned by Precompute) instead of re-parsing each
+// cell's numeric value on every call.
+func CompareCached(a, b []string, ca, cb []KeyValue, keys []Key) int {
+	for i, k := range keys {
+		c := compareValuesCached(valueAt(a, k.Idx), valueAt(b, k.Idx), ca[i], cb[i], k.Mode)
+		if c == 0 {
+			continue
+		}
+		if k.Dir < 0 {
+			c = -c
+		}
+		return c
+	}
+	return 0
+}
+
+func compareValuesCached(a, b string, ca, cb KeyValue, mode Mode) int {
+	if mode != ModeStr && ca.Valid && cb.Valid {
+		switch {
+		case ca.F < cb.F:
+			return -1
+		case ca.F > cb.F:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
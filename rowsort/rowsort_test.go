@@ -0,0 +1,131 @@
+package rowsort
+
+import "testing"
+
+func TestParseKeysMultiKeyWithDirAndMode(t *testing.T) {
+	colIndex := map[string]int{"region": 0, "price": 1, "date": 2}
+
+	keys, err := ParseKeys("region:asc:str,price:desc:num,date:asc", "", colIndex, nil)
+	if err != nil {
+		t.Fatalf("ParseKeys: %v", err)
+	}
+	want := []Key{
+		{Idx: 0, Dir: 1, Mode: ModeStr},
+		{Idx: 1, Dir: -1, Mode: ModeNum},
+		{Idx: 2, Dir: 1, Mode: ModeAuto},
+	}
+	if len(keys) != len(want) {
+		t.Fatalf("got %d keys, want %d", len(keys), len(want))
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("key %d = %+v, want %+v", i, keys[i], want[i])
+		}
+	}
+}
+
+func TestParseKeysSingleColumnFallsBackToLegacyType(t *testing.T) {
+	colIndex := map[string]int{"price": 0}
+
+	keys, err := ParseKeys("price", "desc:num", colIndex, nil)
+	if err != nil {
+		t.Fatalf("ParseKeys: %v", err)
+	}
+	want := Key{Idx: 0, Dir: -1, Mode: ModeNum}
+	if len(keys) != 1 || keys[0] != want {
+		t.Fatalf("got %+v, want [%+v]", keys, want)
+	}
+}
+
+func TestParseKeysMissingColumnErrors(t *testing.T) {
+	if _, err := ParseKeys("missing", "", map[string]int{}, nil); err == nil {
+		t.Fatal("expected an error for a missing sort column")
+	}
+}
+
+func TestParseKeysUnsupportedModeErrors(t *testing.T) {
+	colIndex := map[string]int{"price": 0}
+	if _, err := ParseKeys("price:asc:bogus", "", colIndex, nil); err == nil {
+		t.Fatal("expected an error for an unsupported sort mode")
+	}
+}
+
+func TestParseKeysConsultsIsNumericForAutoMode(t *testing.T) {
+	colIndex := map[string]int{"price": 0}
+	isNumeric := func(col string) bool { return col == "price" }
+
+	keys, err := ParseKeys("price", "", colIndex, isNumeric)
+	if err != nil {
+		t.Fatalf("ParseKeys: %v", err)
+	}
+	if len(keys) != 1 || keys[0].Mode != ModeNum {
+		t.Fatalf("got %+v, want ModeNum via isNumeric", keys)
+	}
+}
+
+func TestValidateNumericRowRejectsNonNumeric(t *testing.T) {
+	keys := []Key{{Idx: 0, Dir: 1, Mode: ModeNum}}
+
+	if err := ValidateNumericRow([]string{"12.5"}, keys); err != nil {
+		t.Fatalf("unexpected error for a numeric value: %v", err)
+	}
+	if err := ValidateNumericRow([]string{"abc"}, keys); err == nil {
+		t.Fatal("expected an error for a non-numeric value under a numeric sort key")
+	}
+	if err := ValidateNumericRow([]string{""}, keys); err == nil {
+		t.Fatal("expected an error for an empty value under a numeric sort key")
+	}
+}
+
+func TestCompareMultiKeyTiebreak(t *testing.T) {
+	keys := []Key{
+		{Idx: 0, Dir: 1, Mode: ModeStr},
+		{Idx: 1, Dir: -1, Mode: ModeNum},
+	}
+
+	a := []string{"JP", "100"}
+	b := []string{"JP", "200"}
+	if !Less(b, a, keys) {
+		t.Error("expected [JP 200] to sort before [JP 100] under a desc numeric tiebreak")
+	}
+
+	c := []string{"CA", "50"}
+	if !Less(c, a, keys) {
+		t.Error("expected [CA 50] to sort before [JP 100] on the primary string key")
+	}
+}
+
+func TestSortStableMultiKey(t *testing.T) {
+	rows := [][]string{
+		{"1", "JP", "100"},
+		{"2", "CA", "200"},
+		{"3", "JP", "50"},
+	}
+	keys := []Key{{Idx: 1, Dir: 1, Mode: ModeStr}, {Idx: 2, Dir: 1, Mode: ModeNum}}
+
+	Sort(rows, keys)
+
+	got := []string{rows[0][0], rows[1][0], rows[2][0]}
+	want := []string{"2", "3", "1"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got order %v, want %v", got, want)
+		}
+	}
+}
+
+func TestPrecomputeAndCompareCachedMatchCompare(t *testing.T) {
+	rows := [][]string{{"10"}, {"20"}, {"abc"}}
+	keys := []Key{{Idx: 0, Dir: 1, Mode: ModeAuto}}
+	cache := Precompute(rows, keys)
+
+	for i := range rows {
+		for j := range rows {
+			got := CompareCached(rows[i], rows[j], cache[i], cache[j], keys)
+			want := Compare(rows[i], rows[j], keys)
+			if got != want {
+				t.Fatalf("CompareCached(%v, %v) = %d, want %d (Compare)", rows[i], rows[j], got, want)
+			}
+		}
+	}
+}
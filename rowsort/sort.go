@@ -0,0 +1,46 @@
+package rowsort
+
+import "sort"
+
+// Sort stable-sorts rows in place against keys.
+func Sort(rows [][]string, keys []Key) {
+	SortFunc(len(rows), keys, func(i int) []string { return rows[i] }, func(i, j int) {
+		rows[i], rows[j] = rows[j], rows[i]
+	})
+}
+
+// SortFunc stable-sorts n items against keys using rowAt to read a row and
+// swap to exchange two items, so a caller that pairs extra per-item data
+// alongside each row (like extsort's seq tiebreaker) can keep it aligned
+// across swaps instead of sorting a bare [][]string. Each row's sort-key
+// values are parsed once via Precompute and reused across all of
+// sort.Stable's O(n log n) comparisons, the same caching Sort itself relies
+// on.
+func SortFunc(n int, keys []Key, rowAt func(i int) []string, swap func(i, j int)) {
+	rows := make([][]string, n)
+	for i := range rows {
+		rows[i] = rowAt(i)
+	}
+	sort.Stable(&funcSort{rows: rows, cache: Precompute(rows, keys), keys: keys, swap: swap})
+}
+
+// funcSort adapts SortFunc's rowAt/swap pair to sort.Interface, keeping a
+// precomputed KeyValue cache paired with each row across swaps.
+type funcSort struct {
+	rows  [][]string
+	cache [][]KeyValue
+	keys  []Key
+	swap  func(i, j int)
+}
+
+func (f *funcSort) Len() int { return len(f.rows) }
+
+func (f *funcSort) Less(i, j int) bool {
+	return CompareCached(f.rows[i], f.rows[j], f.cache[i], f.cache[j], f.keys) < 0
+}
+
+func (f *funcSort) Swap(i, j int) {
+	f.rows[i], f.rows[j] = f.rows[j], f.rows[i]
+	f.cache[i], f.cache[j] = f.cache[j], f.cache[i]
+	f.swap(i, j)
+}
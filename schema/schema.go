@@ -0,0 +1,136 @@
+// Package schema parses the -schema flag's column:type declarations and
+// validates CSV rows against them, so numeric and time-typed columns are
+// known to be well-formed before filtering or sorting touches them.
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type is a declared column type.
+type Type int
+
+const (
+	TypeString Type = iota
+	TypeNum
+	TypeInt
+	TypeBool
+	TypeTime
+)
+
+// ColumnSpec is the declared type of one schema column.
+type ColumnSpec struct {
+	Type Type
+	// TimeFormat is the time.Parse layout for TypeTime columns.
+	TimeFormat string
+}
+
+// Schema maps column name to its declared type.
+type Schema map[string]ColumnSpec
+
+// Parse parses a schema spec such as
+// "price:num,qty:int,active:bool,ts:time=2006-01-02".
+func Parse(spec string) (Schema, error) {
+	sc := make(Schema)
+	for _, seg := range strings.Split(spec, ",") {
+		seg = strings.TrimSpace(seg)
+		if seg == "" {
+			continue
+		}
+
+		parts := strings.SplitN(seg, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid schema column %q: expected name:type", seg)
+		}
+		name := strings.TrimSpace(parts[0])
+
+		col, err := parseColumnSpec(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("schema column %q: %w", name, err)
+		}
+		sc[name] = col
+	}
+	return sc, nil
+}
+
+func parseColumnSpec(typeSpec string) (ColumnSpec, error) {
+	if base, layout, ok := strings.Cut(typeSpec, "="); ok && strings.EqualFold(base, "time") {
+		return ColumnSpec{Type: TypeTime, TimeFormat: layout}, nil
+	}
+
+	switch strings.ToLower(typeSpec) {
+	case "time":
+		return ColumnSpec{Type: TypeTime, TimeFormat: time.RFC3339}, nil
+	case "num", "number", "float":
+		return ColumnSpec{Type: TypeNum}, nil
+	case "int", "integer":
+		return ColumnSpec{Type: TypeInt}, nil
+	case "bool", "boolean":
+		return ColumnSpec{Type: TypeBool}, nil
+	case "str", "string", "text":
+		return ColumnSpec{Type: TypeString}, nil
+	default:
+		return ColumnSpec{}, fmt.Errorf("unsupported type %q", typeSpec)
+	}
+}
+
+// Parse parses a single raw cell value according to the column's declared
+// type.
+func (c ColumnSpec) Parse(raw string) (any, error) {
+	raw = strings.TrimSpace(raw)
+	switch c.Type {
+	case TypeNum:
+		return strconv.ParseFloat(raw, 64)
+	case TypeInt:
+		return strconv.ParseInt(raw, 10, 64)
+	case TypeBool:
+		return strconv.ParseBool(raw)
+	case TypeTime:
+		return time.Parse(c.TimeFormat, raw)
+	default:
+		return raw, nil
+	}
+}
+
+// IsNumeric reports whether col is declared as a numeric (num or int)
+// column, letting callers like the sort comparator skip probing whether a
+// value parses as a float.
+func (s Schema) IsNumeric(col string) bool {
+	c, ok := s[col]
+	return ok && (c.Type == TypeNum || c.Type == TypeInt)
+}
+
+// Validate parses every declared column's value in each data row (no
+// header row included), returning an error describing the first row that
+// doesn't conform.
+func (s Schema) Validate(colIndex map[string]int, rows [][]string) error {
+	for i, row := range rows {
+		if err := s.ValidateRow(colIndex, row); err != nil {
+			return fmt.Errorf("schema: row %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// ValidateRow parses every declared column's value in row, so a streaming
+// caller can validate each row as it's read rather than buffering the whole
+// table first.
+func (s Schema) ValidateRow(colIndex map[string]int, row []string) error {
+	for name, col := range s {
+		idx, ok := colIndex[name]
+		if !ok {
+			return fmt.Errorf("schema column not found: %s", name)
+		}
+		v := ""
+		if idx < len(row) {
+			v = row[idx]
+		}
+		if _, err := col.Parse(v); err != nil {
+			return fmt.Errorf("column %s: %w", name, err)
+		}
+	}
+	return nil
+}
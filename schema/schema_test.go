@@ -0,0 +1,123 @@
+package schema
+
+import "testing"
+
+func TestParseColumnTypes(t *testing.T) {
+	sc, err := Parse("price:num,qty:int,active:bool,ts:time=2006-01-02,name:str")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	cases := []struct {
+		col  string
+		want Type
+	}{
+		{"price", TypeNum},
+		{"qty", TypeInt},
+		{"active", TypeBool},
+		{"ts", TypeTime},
+		{"name", TypeString},
+	}
+	for _, c := range cases {
+		spec, ok := sc[c.col]
+		if !ok {
+			t.Fatalf("missing column %q", c.col)
+		}
+		if spec.Type != c.want {
+			t.Errorf("%s: got type %v, want %v", c.col, spec.Type, c.want)
+		}
+	}
+	if sc["ts"].TimeFormat != "2006-01-02" {
+		t.Errorf("ts.TimeFormat = %q, want 2006-01-02", sc["ts"].TimeFormat)
+	}
+}
+
+func TestParseInvalidColumn(t *testing.T) {
+	if _, err := Parse("price"); err == nil {
+		t.Fatal("expected an error for a column missing a type")
+	}
+	if _, err := Parse("price:bogus"); err == nil {
+		t.Fatal("expected an error for an unsupported type")
+	}
+}
+
+func TestColumnSpecParse(t *testing.T) {
+	sc, err := Parse("price:num,qty:int,active:bool,ts:time=2006-01-02")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if _, err := sc["price"].Parse("12.5"); err != nil {
+		t.Errorf("price.Parse: %v", err)
+	}
+	if _, err := sc["price"].Parse("abc"); err == nil {
+		t.Error("expected an error parsing a non-numeric value as num")
+	}
+	if _, err := sc["qty"].Parse("7"); err != nil {
+		t.Errorf("qty.Parse: %v", err)
+	}
+	if v, err := sc["active"].Parse("true"); err != nil || v != true {
+		t.Errorf("active.Parse(true) = %v, %v", v, err)
+	}
+	if _, err := sc["ts"].Parse("2021-06-01"); err != nil {
+		t.Errorf("ts.Parse: %v", err)
+	}
+	if _, err := sc["ts"].Parse("not-a-date"); err == nil {
+		t.Error("expected an error parsing an invalid date")
+	}
+}
+
+func TestIsNumeric(t *testing.T) {
+	sc, err := Parse("price:num,qty:int,name:str")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !sc.IsNumeric("price") || !sc.IsNumeric("qty") {
+		t.Error("expected price and qty to be numeric")
+	}
+	if sc.IsNumeric("name") {
+		t.Error("expected name not to be numeric")
+	}
+	if sc.IsNumeric("missing") {
+		t.Error("expected an undeclared column not to be numeric")
+	}
+}
+
+func TestValidateRow(t *testing.T) {
+	sc, err := Parse("price:num")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	colIndex := map[string]int{"price": 0, "name": 1}
+
+	if err := sc.ValidateRow(colIndex, []string{"12.5", "Alice"}); err != nil {
+		t.Errorf("ValidateRow: %v", err)
+	}
+	if err := sc.ValidateRow(colIndex, []string{"abc", "Alice"}); err == nil {
+		t.Error("expected an error for a non-numeric value in a declared numeric column")
+	}
+}
+
+func TestValidateReportsRowNumber(t *testing.T) {
+	sc, err := Parse("price:num")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	colIndex := map[string]int{"price": 0}
+	rows := [][]string{{"12.5"}, {"abc"}}
+
+	err = sc.Validate(colIndex, rows)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestValidateRowMissingColumn(t *testing.T) {
+	sc, err := Parse("price:num")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if err := sc.ValidateRow(map[string]int{}, []string{"12.5"}); err == nil {
+		t.Fatal("expected an error when a declared column isn't in colIndex")
+	}
+}